@@ -4,11 +4,19 @@ package main
 
 import (
 	business "cli-calculator/internal/business"
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/config"
 	"cli-calculator/internal/constants"
+	apperrors "cli-calculator/internal/errors"
 	"cli-calculator/internal/logger"
+	"cli-calculator/internal/system"
+	"cli-calculator/internal/util"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"time"
 )
 
 // Command-line flags
@@ -18,7 +26,27 @@ var (
 	flagHelp      = flag.Bool("help", false, "Show help information")
 	flagVerbose   = flag.Bool("verbose", false, "Enable verbose logging (debug level)")
 	flagNoColor   = flag.Bool("no-color", false, "Disable colored output")
+	flagClear     = flag.Bool("clear", true, "Clear the screen between menus (use -clear=false to disable)")
 	flagPrecision = flag.Int("precision", constants.DefaultPrecision, "Number of decimal places for results (0-15)")
+	flagConfig    = flag.String("config", "", "Path to the configuration file (default: ~/"+constants.ConfigFileName+")")
+	flagExpr      = flag.String("expr", "", "Evaluate an expression (e.g. \"2 + 3 * 4\") and exit")
+	flagBatch     = flag.String("batch", "", "Evaluate a file of expressions, one per line, and exit (Ctrl+C stops and keeps results so far)")
+	flagExplain   = flag.String("explain", "", "Describe an operation (e.g. \"modulo\" or \"%\") and exit")
+	flagBench     = flag.Bool("bench", false, "Benchmark each operation, print ns/op, and exit")
+	flagSelfTest  = flag.Bool("selftest", false, "Check every operation against known results and exit (1 if any fail)")
+	flagReport    = flag.String("report", "", "Write a history statistics report to the given file and exit")
+	flagOutput    = flag.String("output-format", constants.OutputFormatText, "Output format for -expr results and errors (text or json)")
+	flagRPN       = flag.Bool("rpn", false, "Start a reverse Polish notation REPL (e.g. \"5 3 + 2 *\") instead of the menu")
+	flagReplay    = flag.String("replay", "", "Re-evaluate every expression in a history file and report mismatches")
+	flagForce     = flag.Bool("force", false, "Overwrite existing files (e.g. the -report file) without asking")
+	flagMaxHist   = flag.Int("max-history", -1, "Maximum history entries (0-10000), overriding the config; -1 keeps the configured size")
+	flagStats     = flag.Bool("stats", false, "Print history statistics and exit (JSON with -output-format json)")
+	flagSeedHist  = flag.Int("seed-history", 0, "Pre-populate history with N random calculations (for demos)")
+	flagSeed      = flag.Int64("seed", 0, "Random seed for -seed-history (0 picks a new seed each run)")
+	flagSchema    = flag.Bool("print-config-schema", false, "Print a JSON Schema describing the config file and exit")
+	flagCheck     = flag.String("check", "", "Validate a config or history file and exit (0 if valid)")
+	flagCPUProf   = flag.String("cpuprofile", "", "Write a CPU profile to the given file (inspect with go tool pprof)")
+	flagCopy      = flag.Bool("copy", false, "Copy each result to the clipboard (uses pbcopy, clip, or xclip)")
 )
 
 // main is the entry point of the application.
@@ -27,18 +55,85 @@ func main() {
 	// Parse command-line flags
 	flag.Parse()
 
+	// Profile everything that follows, including one-shot modes like -bench
+	if *flagCPUProf != "" {
+		stop, err := system.StartCPUProfile(*flagCPUProf)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Cannot write CPU profile: %v\n", err)
+			os.Exit(int(constants.ExitFileError))
+		}
+		stopProfile = stop
+	}
+
 	// Handle special flags
 	if *flagVersion {
 		showVersion()
-		os.Exit(int(constants.ExitSuccess))
+		exit(constants.ExitSuccess)
 	}
 
 	if *flagHelp {
 		showHelp()
-		os.Exit(int(constants.ExitSuccess))
+		exit(constants.ExitSuccess)
+	}
+
+	if *flagExplain != "" {
+		description, err := calculator.ExplainOperation(*flagExplain)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitInvalidInput)
+		}
+		fmt.Println(description)
+		exit(constants.ExitSuccess)
+	}
+
+	if *flagBench {
+		showBenchmarks()
+		exit(constants.ExitSuccess)
+	}
+
+	if *flagSelfTest {
+		if !runSelfTest() {
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	if *flagCheck != "" {
+		kind, err := business.CheckFile(*flagCheck)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s check failed: %v\n", checkKindName(kind), err)
+			if errors.Is(err, apperrors.ErrConfigInvalid) {
+				exit(constants.ExitConfigError)
+			}
+			exit(constants.ExitFileError)
+		}
+		fmt.Printf("%s: valid %s file\n", *flagCheck, kind)
+		exit(constants.ExitSuccess)
+	}
+
+	if *flagSchema {
+		if err := config.WriteSchema(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// Validate output format
+	if *flagOutput != constants.OutputFormatText && *flagOutput != constants.OutputFormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: Output format must be %q or %q\n", constants.OutputFormatText, constants.OutputFormatJSON)
+		exit(constants.ExitInvalidInput)
+	}
+
+	// Keep stdout clean for machine-readable output
+	if *flagOutput == constants.OutputFormatJSON {
+		logger.GetDefaultLogger().SetOutput(os.Stderr)
 	}
 
 	// Configure logging based on flags
+	if *flagNoColor {
+		logger.GetDefaultLogger().SetColorize(false)
+	}
 	if *flagVerbose {
 		logger.SetLevel(constants.LogLevelDebug)
 		logger.Info("Verbose logging enabled")
@@ -48,21 +143,20 @@ func main() {
 	logger.Info("Starting %s v%s", constants.AppName, constants.AppVersion)
 
 	// Create and initialize the service
-	service, err := business.NewService()
+	service, err := business.NewService(*flagConfig)
 	if err != nil {
 		logger.Error("Failed to initialize service: %v", err)
 		fmt.Fprintf(os.Stderr, "Error: Failed to initialize application: %v\n", err)
-		os.Exit(int(constants.ExitError))
+		exit(constants.ExitError)
 	}
 
 	// Apply command-line flag overrides to configuration
-	if *flagPrecision != constants.DefaultPrecision {
-		if *flagPrecision < 0 || *flagPrecision > 15 {
+	if isFlagSet("precision") {
+		if err := service.SetPrecision(*flagPrecision); err != nil {
 			logger.Error("Invalid precision value: %d (must be 0-15)", *flagPrecision)
 			fmt.Fprintf(os.Stderr, "Error: Precision must be between 0 and 15\n")
-			os.Exit(int(constants.ExitInvalidInput))
+			exit(constants.ExitInvalidInput)
 		}
-		service.Config.Precision = *flagPrecision
 		logger.Debug("Precision set to %d via command-line flag", *flagPrecision)
 	}
 
@@ -71,17 +165,227 @@ func main() {
 		logger.Debug("Color output disabled via command-line flag")
 	}
 
+	if !*flagClear {
+		service.Config.ClearScreen = false
+		logger.Debug("Screen clearing disabled via command-line flag")
+	}
+
+	if *flagMaxHist != -1 {
+		if err := service.SetMaxHistory(*flagMaxHist); err != nil {
+			logger.Error("Invalid max history value: %d", *flagMaxHist)
+			fmt.Fprintf(os.Stderr, "Error: -max-history must be between 0 and 10000\n")
+			exit(constants.ExitInvalidInput)
+		}
+		logger.Debug("Max history set to %d via command-line flag", *flagMaxHist)
+	}
+
+	service.Verbose = *flagVerbose
+	service.OutputFormat = *flagOutput
+	service.CopyResult = *flagCopy
+
+	// Demo mode: fill history with random calculations before starting
+	if *flagSeedHist < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -seed-history must not be negative\n")
+		exit(constants.ExitInvalidInput)
+	}
+	if *flagSeedHist > 0 {
+		seed := *flagSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		added := service.SeedHistory(*flagSeedHist, seed)
+		logger.Info("Seeded history with %d calculations (seed %d)", added, seed)
+	}
+
+	// One-shot mode: evaluate the expression and exit without the menu
+	if *flagExpr != "" {
+		if err := service.EvaluateExpression(*flagExpr); err != nil {
+			logger.Error("Expression error: %v", err)
+			// JSON errors were already written to stdout by the service
+			if *flagOutput != constants.OutputFormatJSON {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			exit(exitCodeFor(err))
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// One-shot mode from arguments after "--": calculator -- add 5 3
+	if args := flag.Args(); len(args) > 0 {
+		if err := service.CalculateArgs(args); err != nil {
+			logger.Error("Argument calculation error: %v", err)
+			// JSON errors were already written to stdout by the service
+			if *flagOutput != constants.OutputFormatJSON {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			exit(exitCodeFor(err))
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// Batch mode: evaluate a file of expressions; Ctrl+C stops after the current one
+	if *flagBatch != "" {
+		ctx, stop := system.InterruptContext(context.Background())
+		result, err := service.RunBatch(ctx, *flagBatch, os.Stdout, os.Stderr)
+		stop()
+		if err != nil {
+			logger.Error("Batch error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitFileError)
+		}
+		if result.Cancelled || result.Failed > 0 {
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// Stats mode: print statistics for dashboards and exit
+	if *flagStats {
+		if err := service.WriteStatistics(os.Stdout); err != nil {
+			logger.Error("Stats error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// Report mode: write the statistics report and exit
+	if *flagReport != "" {
+		if !*flagForce {
+			ok, err := util.ConfirmOverwrite(*flagReport)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(constants.ExitFileError)
+			}
+			if !ok {
+				fmt.Println("Report not written (use -force to overwrite)")
+				exit(constants.ExitError)
+			}
+		}
+		if err := service.WriteReport(*flagReport); err != nil {
+			logger.Error("Report error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitFileError)
+		}
+		fmt.Printf("Statistics report written to %s\n", *flagReport)
+		exit(constants.ExitSuccess)
+	}
+
+	// Replay mode: verify a history file against the current evaluator
+	if *flagReplay != "" {
+		report, err := service.Replay(*flagReplay)
+		if err != nil {
+			logger.Error("Replay error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitFileError)
+		}
+		if err := business.WriteReplayReport(os.Stdout, report); err != nil {
+			logger.Error("Failed to write replay report: %v", err)
+		}
+		if len(report.Mismatches) > 0 {
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// RPN mode: a stack-calculator REPL instead of the menu
+	if *flagRPN {
+		if err := service.RunRPN(); err != nil {
+			logger.Error("RPN error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(constants.ExitError)
+		}
+		exit(constants.ExitSuccess)
+	}
+
+	// The menu notices a closed stdout (e.g. piped into head) and saves
+	// history before exiting, so it needs the write error rather than SIGPIPE.
+	// Other modes keep the default and are stopped by the signal.
+	system.IgnoreBrokenPipe()
+
 	// Run the application
 	// This demonstrates proper error handling and exit codes
 	if err := service.Run(); err != nil {
 		logger.Error("Application error: %v", err)
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(int(constants.ExitError))
+		exit(constants.ExitError)
 	}
 
 	// Successful exit
 	logger.Info("Application terminated successfully")
-	os.Exit(int(constants.ExitSuccess))
+	exit(constants.ExitSuccess)
+}
+
+// stopProfile finishes the -cpuprofile output; it does nothing when
+// profiling is off.
+var stopProfile = func() error { return nil }
+
+// exit stops any CPU profile and exits with code. Deferred calls don't run on
+// os.Exit, so every exit after profiling starts must go through here.
+func exit(code constants.ExitCode) {
+	if err := stopProfile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to write CPU profile: %v\n", err)
+	}
+	os.Exit(int(code))
+}
+
+// checkKindName names the file kind in -check errors; the kind is empty
+// when the file couldn't be read or parsed at all.
+func checkKindName(kind string) string {
+	if kind == "" {
+		return "file"
+	}
+	return kind
+}
+
+// isFlagSet reports whether the named flag was given on the command line,
+// so an explicit value can override the config even when it equals the default.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// exitCodeFor maps an error to the most specific exit code.
+// Invalid user input gets its own code so scripts can tell it apart.
+func exitCodeFor(err error) constants.ExitCode {
+	var validationErr *apperrors.ValidationError
+	if errors.As(err, &validationErr) || errors.Is(err, apperrors.ErrInvalidInput) {
+		return constants.ExitInvalidInput
+	}
+	return constants.ExitError
+}
+
+// showBenchmarks runs the operation benchmarks and prints a table.
+func showBenchmarks() {
+	fmt.Println("Benchmarking operations (about one second each)...")
+	fmt.Println()
+	fmt.Printf("%-16s %14s %12s\n", "OPERATION", "ITERATIONS", "NS/OP")
+	for _, result := range calculator.RunBenchmarks() {
+		fmt.Printf("%-16s %14d %12d\n", result.Operation.String(), result.Iterations, result.NsPerOp)
+	}
+}
+
+// runSelfTest checks every operation, prints PASS or FAIL for each, and
+// reports whether all of them passed.
+func runSelfTest() bool {
+	failed := 0
+	results := calculator.RunSelfTest()
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Printf("PASS  %-16s %v = %g\n", result.Operation.String(), result.Case.Operands, result.Got)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL  %-16s %v\n", result.Operation.String(), result.Err)
+	}
+
+	fmt.Printf("\n%d/%d operations passed\n", len(results)-failed, len(results))
+	return failed == 0
 }
 
 // showVersion displays version information.
@@ -94,7 +398,7 @@ func showVersion() {
 func showHelp() {
 	fmt.Printf("%s - A production-grade CLI calculator\n\n", constants.AppName)
 	fmt.Println("USAGE:")
-	fmt.Printf("  %s [options]\n\n", os.Args[0])
+	fmt.Printf("  %s [options] [-- <operation> <operands...>]\n\n", os.Args[0])
 	fmt.Println("OPTIONS:")
 	flag.PrintDefaults()
 	fmt.Println("\nEXAMPLES:")
@@ -104,9 +408,46 @@ func showHelp() {
 	fmt.Printf("    %s -precision 5\n\n", os.Args[0])
 	fmt.Println("  Start with verbose logging:")
 	fmt.Printf("    %s -verbose\n\n", os.Args[0])
+	fmt.Println("  Evaluate an expression and show each step:")
+	fmt.Printf("    %s -verbose -expr \"2 + 3 * 4\"\n\n", os.Args[0])
+	fmt.Println("  Calculate straight from arguments (operation names or symbols):")
+	fmt.Printf("    %s -- add 5 3\n\n", os.Args[0])
+	fmt.Println("  Evaluate an expression with JSON output:")
+	fmt.Printf("    %s -expr \"10 / 4\" -output-format json\n\n", os.Args[0])
+	fmt.Println("  Evaluate an expression and copy the result to the clipboard:")
+	fmt.Printf("    %s -copy -expr \"12 * 7\"\n\n", os.Args[0])
+	fmt.Println("  Evaluate a file of expressions with progress (Ctrl+C keeps what's done):")
+	fmt.Printf("    %s -batch expressions.txt\n\n", os.Args[0])
+	fmt.Println("  Keep only the 20 most recent history entries:")
+	fmt.Printf("    %s -max-history 20\n\n", os.Args[0])
+	fmt.Println("  Print history statistics as JSON:")
+	fmt.Printf("    %s -stats -output-format json\n\n", os.Args[0])
+	fmt.Println("  Save a statistics report of your history (-force skips the overwrite prompt):")
+	fmt.Printf("    %s -report stats.txt -force\n\n", os.Args[0])
+	fmt.Println("  Demo with 20 reproducible history entries:")
+	fmt.Printf("    %s -seed-history 20 -seed 1\n\n", os.Args[0])
+	fmt.Println("  Check a history file still reproduces (exit code 1 on mismatch):")
+	fmt.Printf("    %s -replay ~/%s\n\n", os.Args[0], constants.HistoryFileName)
+	fmt.Println("  Use the stack-based RPN calculator:")
+	fmt.Printf("    %s -rpn\n\n", os.Args[0])
+	fmt.Println("  Learn what an operation does:")
+	fmt.Printf("    %s -explain modulo\n\n", os.Args[0])
+	fmt.Println("  Compare the cost of each operation:")
+	fmt.Printf("    %s -bench\n\n", os.Args[0])
+	fmt.Println("  Verify a built binary calculates correctly (exit code 1 on failure):")
+	fmt.Printf("    %s -selftest\n\n", os.Args[0])
+	fmt.Println("  Profile a run and inspect where the time went:")
+	fmt.Printf("    %s -cpuprofile cpu.prof -bench && go tool pprof cpu.prof\n\n", os.Args[0])
+	fmt.Println("  Validate a config or history file in CI (non-zero exit if invalid):")
+	fmt.Printf("    %s -check ./calculator.json\n\n", os.Args[0])
+	fmt.Println("  Print the config file schema for editor validation:")
+	fmt.Printf("    %s -print-config-schema > calculator.schema.json\n\n", os.Args[0])
+	fmt.Println("  Start with a project-local configuration:")
+	fmt.Printf("    %s -config ./calculator.json\n\n", os.Args[0])
 	fmt.Println("\nFEATURES:")
 	fmt.Println("  - Basic arithmetic operations (+, -, *, /)")
 	fmt.Println("  - Advanced operations (power, square root, modulo, factorial)")
+	fmt.Println("  - Expression evaluation with operator precedence and parentheses")
 	fmt.Println("  - Calculation history with statistics")
 	fmt.Println("  - Configurable settings with file persistence")
 	fmt.Println("  - Comprehensive error handling")
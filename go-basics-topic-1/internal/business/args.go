@@ -0,0 +1,117 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"cli-calculator/internal/validation"
+	"fmt"
+	"strings"
+)
+
+// ParseOperationArgs parses command-line arguments of the form
+// "<operation> <operands...>", e.g. ["add", "5", "3"] or ["^", "2", "10"].
+// The operation is resolved through constants.OperationAliases and each
+// operand is validated like a number typed at a prompt. Operations with a
+// fixed number of operands reject extra ones instead of ignoring them.
+func (s *Service) ParseOperationArgs(args []string) (constants.Operation, []float64, error) {
+	if len(args) == 0 {
+		return constants.OpUnknown, nil, errors.NewValidationError("operation", "", "usage: -- <operation> <operands...> (e.g. -- add 5 3)")
+	}
+
+	operation, ok := constants.LookupOperation(args[0])
+	if !ok {
+		return constants.OpUnknown, nil, errors.NewValidationError(
+			"operation",
+			args[0],
+			"unknown operation (use a name like \"divide\" or a symbol like \"/\")",
+		)
+	}
+
+	if n := fixedArity(operation); n > 0 && len(args)-1 != n {
+		return operation, nil, errors.NewValidationError(
+			"operands",
+			strings.Join(args[1:], " "),
+			fmt.Sprintf("%s takes %d operand(s), got %d", operation.String(), n, len(args)-1),
+		)
+	}
+
+	opts := s.calcOptions()
+	operands := make([]float64, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		value, err := validation.ValidateNumberInRange(arg, s.Config.DecimalSeparator, opts.MinOperand, opts.MaxOperand)
+		if err != nil {
+			return operation, nil, err
+		}
+		operands = append(operands, value)
+	}
+
+	return operation, operands, nil
+}
+
+// fixedArity returns how many operands operation takes on the command line,
+// or 0 when it accepts a list (the calculator still checks the minimum).
+func fixedArity(operation constants.Operation) int {
+	switch operation {
+	case constants.OpAddition, constants.OpSubtraction, constants.OpMultiplication, constants.OpDivision, constants.OpAverage:
+		return 0
+	case constants.OpSquareRoot, constants.OpFactorial:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// CalculateArgs computes the operation given as command-line arguments (see
+// ParseOperationArgs), prints "expression = result" (or JSON with the JSON
+// output format), and records it in history like an -expr result.
+func (s *Service) CalculateArgs(args []string) error {
+	operation, operands, err := s.ParseOperationArgs(args)
+	if err == nil {
+		err = s.calculateArgs(operation, operands)
+	}
+	if err != nil && s.OutputFormat == constants.OutputFormatJSON {
+		if jsonErr := util.PrintErrorJSON(err); jsonErr != nil {
+			logger.Error("Failed to write JSON error: %v", jsonErr)
+		}
+	}
+	return err
+}
+
+// calculateArgs computes, prints, and records one parsed command-line calculation.
+func (s *Service) calculateArgs(operation constants.Operation, operands []float64) error {
+	// Operands as typed, so "-- add 1.2345 1" shows 1.2345 + 1
+	expression := formatExpression(operation, operands, plainNumber)
+
+	result, err := calculator.CalculateWithOptions(operation, operands, s.calcOptions())
+	s.recordAudit(operation.String(), expression, result.Value, err)
+	if err != nil {
+		if s.Config.SaveHistory {
+			s.History.AddError(operation.String(), expression, err)
+		}
+		return err
+	}
+
+	// Shown like the menu shows it (2:3 (0.67), 25.00%); JSON and the
+	// clipboard get the plain number
+	formatted := s.formatCalculation(result)
+	resultStr := s.renderResult(result)
+	if s.OutputFormat == constants.OutputFormatJSON {
+		if err := util.PrintJSON(expressionOutput{Expression: expression, Result: result.Value, Formatted: formatted}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(util.DefaultIO().Out, "%s = %s\n", expression, resultStr)
+	}
+	s.copyResult(formatted)
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess(operation.String(), expression, calculator.RoundTo(result.Value, s.Config.PrecisionFor(operation)))
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("Calculated from arguments: %s = %s", expression, resultStr)
+	return nil
+}
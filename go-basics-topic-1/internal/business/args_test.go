@@ -0,0 +1,121 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/util"
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+// TestParseOperationArgs tests resolving the operation and operands from argv.
+func TestParseOperationArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		operation constants.Operation
+		operands  []float64
+		field     string // Field named in the ValidationError; empty means valid
+	}{
+		{"add 5 3", []string{"add", "5", "3"}, constants.OpAddition, []float64{5, 3}, ""},
+		{"symbol", []string{"^", "2", "10"}, constants.OpPower, []float64{2, 10}, ""},
+		{"case-insensitive alias", []string{"SQRT", "16"}, constants.OpSquareRoot, []float64{16}, ""},
+		{"list of operands", []string{"avg", "2", "4", "6"}, constants.OpAverage, []float64{2, 4, 6}, ""},
+		{"negative operand", []string{"sub", "5", "-3"}, constants.OpSubtraction, []float64{5, -3}, ""},
+		{"unknown operation", []string{"logarithm", "8"}, constants.OpUnknown, nil, "operation"},
+		{"no arguments", nil, constants.OpUnknown, nil, "operation"},
+		{"bad operand", []string{"add", "5", "three"}, constants.OpAddition, nil, "number"},
+		{"too many operands", []string{"pow", "2", "3", "4"}, constants.OpPower, nil, "operands"},
+		{"too few operands", []string{"sqrt"}, constants.OpSquareRoot, nil, "operands"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+
+			operation, operands, err := s.ParseOperationArgs(tt.args)
+			if operation != tt.operation {
+				t.Errorf("%s: expected operation %s, got %s", tt.name, tt.operation, operation)
+			}
+			if tt.field != "" {
+				var validationErr *errors.ValidationError
+				if !stderrors.As(err, &validationErr) || validationErr.Field != tt.field {
+					t.Errorf("%s: expected ValidationError for %s, got %v", tt.name, tt.field, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if len(operands) != len(tt.operands) {
+				t.Fatalf("%s: expected operands %v, got %v", tt.name, tt.operands, operands)
+			}
+			for i := range operands {
+				if operands[i] != tt.operands[i] {
+					t.Errorf("%s: expected operands %v, got %v", tt.name, tt.operands, operands)
+				}
+			}
+		})
+	}
+}
+
+// TestCalculateArgs tests computing, printing, and recording from argv.
+func TestCalculateArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		hasError bool
+	}{
+		{"add 5 3", []string{"add", "5", "3"}, "5 + 3 = 8\n", false},
+		{"three operands", []string{"*", "2", "3", "4"}, "2 * 3 * 4 = 24\n", false},
+		{"operands shown in full", []string{"add", "1.2345", "1"}, "1.2345 + 1 = 2.23\n", false},
+		{"single operand", []string{"sqrt", "2.25"}, "√2.25 = 1.50\n", false},
+		{"ratio like the menu", []string{"ratio", "2", "3"}, "2:3 = 2:3 (0.67)\n", false},
+		{"percent change like the menu", []string{"pct", "200", "250"}, "200 → 250 = 25%\n", false},
+		{"overflow", append([]string{"multiply"}, strings.Fields(strings.Repeat("1e15 ", 22))...), "", true},
+		{"divide by zero", []string{"div", "1", "0"}, "", true},
+		{"unknown operation", []string{"frobnicate", "1"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+
+			var out bytes.Buffer
+			previous := util.SetDefaultIO(util.NewIO(strings.NewReader(""), &out))
+			t.Cleanup(func() { util.SetDefaultIO(previous) })
+
+			err := s.CalculateArgs(tt.args)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				if out.Len() != 0 {
+					t.Errorf("%s: expected no result printed, got %q", tt.name, out.String())
+				}
+				if n := len(s.History.GetSuccessful()); n != 0 {
+					t.Errorf("%s: expected no successful entry, got %d", tt.name, n)
+				}
+				if err := s.History.Save(); err != nil {
+					t.Errorf("%s: history no longer saves: %v", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, out.String())
+			}
+			if s.History.Count() != 1 {
+				t.Errorf("%s: expected 1 history entry, got %d", tt.name, s.History.Count())
+			}
+			if err := s.History.Save(); err != nil {
+				t.Errorf("%s: history no longer saves: %v", tt.name, err)
+			}
+		})
+	}
+}
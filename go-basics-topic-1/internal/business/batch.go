@@ -0,0 +1,139 @@
+package businessService
+
+import (
+	"bufio"
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/system"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// batchProgressEvery is how many expressions pass between progress reports.
+// It is a variable so tests can report on every line.
+var batchProgressEvery = 100
+
+// BatchResult summarizes a batch run.
+type BatchResult struct {
+	Total        int  // Expressions in the file
+	Processed    int  // Expressions evaluated before the run ended
+	Failed       int  // Processed expressions that returned an error
+	Cancelled    bool // True when the context was cancelled before the last expression
+	OutputClosed bool // True when out stopped accepting results (e.g. piped into head)
+}
+
+// batchLine is one expression from a batch file with its line number.
+type batchLine struct {
+	number     int
+	expression string
+}
+
+// RunBatch evaluates every expression in the file at path, one per line,
+// writing each result to out and "processed n/total" reports to progress.
+// Blank lines and lines starting with # are skipped. Results are recorded
+// in history like -expr results and saved once at the end.
+//
+// Cancelling ctx (e.g. on Ctrl+C) stops before the next expression; the
+// expressions processed so far are still recorded and saved, and the
+// returned result has Cancelled set. A broken pipe on out stops the run the
+// same way, with OutputClosed set, since nobody is reading the results.
+// This demonstrates context cancellation in a long-running loop.
+func (s *Service) RunBatch(ctx context.Context, path string, out, progress io.Writer) (BatchResult, error) {
+	var result BatchResult
+
+	lines, err := readBatchFile(path)
+	if err != nil {
+		return result, err
+	}
+	result.Total = len(lines)
+
+	for _, line := range lines {
+		// Check between expressions so each one is either fully recorded or not at all
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			break
+		}
+
+		failed, err := s.evaluateBatchLine(line, out)
+		if failed {
+			result.Failed++
+		}
+		result.Processed++
+		if system.IsBrokenPipe(err) {
+			result.OutputClosed = true
+			break
+		}
+
+		if result.Processed%batchProgressEvery == 0 || result.Processed == result.Total {
+			fmt.Fprintf(progress, "processed %d/%d\n", result.Processed, result.Total)
+		}
+	}
+
+	if result.Cancelled {
+		fmt.Fprintf(progress, "cancelled after %d/%d\n", result.Processed, result.Total)
+	}
+	if result.OutputClosed {
+		logger.Info("Batch output closed after %d/%d; stopping", result.Processed, result.Total)
+	}
+
+	if s.Config.SaveHistory && result.Processed > 0 {
+		if err := s.History.Save(); err != nil {
+			return result, err
+		}
+	}
+
+	logger.Info("Batch %s: %d/%d processed, %d failed", path, result.Processed, result.Total, result.Failed)
+	return result, nil
+}
+
+// readBatchFile returns the expressions in a batch file.
+func readBatchFile(path string) ([]batchLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewFileError(path, "read", err)
+	}
+	defer file.Close()
+
+	// Read every line up front so progress can show the total
+	var lines []batchLine
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		expression := strings.TrimSpace(scanner.Text())
+		if expression == "" || strings.HasPrefix(expression, "#") {
+			continue
+		}
+		lines = append(lines, batchLine{number: number, expression: expression})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewFileError(path, "read", err)
+	}
+
+	return lines, nil
+}
+
+// evaluateBatchLine evaluates and records one expression, writing its
+// result or error to out. failed reports whether the expression returned an
+// error; err is the error from writing to out.
+func (s *Service) evaluateBatchLine(line batchLine, out io.Writer) (failed bool, err error) {
+	evaluated, _, err := calculator.EvaluateResult(line.expression, s.calcOptions())
+	value := evaluated.Value
+	s.recordAudit("Expression", line.expression, value, err)
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("batch line %d", line.number))
+		if s.Config.SaveHistory {
+			s.History.AddError("Expression", line.expression, err)
+		}
+		_, err = fmt.Fprintf(out, "%s: error: %v\n", line.expression, err)
+		return true, err
+	}
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess("Expression", line.expression, calculator.RoundTo(value, s.Config.Precision))
+	}
+	_, err = fmt.Fprintf(out, "%s = %s\n", line.expression, s.formatCalculation(evaluated))
+	return false, err
+}
@@ -0,0 +1,138 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/history"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBatchFile writes lines to a batch file in a temp directory.
+func writeBatchFile(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "batch.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write batch file: %v", err)
+	}
+	return path
+}
+
+// TestRunBatch tests results, skipped lines, failures, and progress output.
+func TestRunBatch(t *testing.T) {
+	s := newTestService(t, "")
+	path := writeBatchFile(t, "# totals", "2 + 3", "", "10 / 0", "2 ^ 3")
+
+	previous := batchProgressEvery
+	batchProgressEvery = 2
+	t.Cleanup(func() { batchProgressEvery = previous })
+
+	var out, progress bytes.Buffer
+	result, err := s.RunBatch(context.Background(), path, &out, &progress)
+	if err != nil {
+		t.Fatalf("RunBatch returned error: %v", err)
+	}
+
+	expected := BatchResult{Total: 3, Processed: 3, Failed: 1}
+	if result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	for _, want := range []string{"2 + 3 = 5", "10 / 0: error: batch line 4:", "2 ^ 3 = 8"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if progress.String() != "processed 2/3\nprocessed 3/3\n" {
+		t.Errorf("Unexpected progress output:\n%s", progress.String())
+	}
+	if s.History.Count() != 3 {
+		t.Errorf("Expected 3 history entries, got %d", s.History.Count())
+	}
+}
+
+// TestRunBatchOutputClosed tests that a batch stops at the first result
+// nobody can read, keeping what it evaluated, instead of running to the end.
+func TestRunBatchOutputClosed(t *testing.T) {
+	s := newTestService(t, "")
+	path := writeBatchFile(t, "1 + 1", "2 + 2", "3 + 3", "4 + 4")
+
+	var progress bytes.Buffer
+	result, err := s.RunBatch(context.Background(), path, brokenPipeWriter{}, &progress)
+	if err != nil {
+		t.Fatalf("RunBatch returned error: %v", err)
+	}
+
+	expected := BatchResult{Total: 4, Processed: 1, OutputClosed: true}
+	if result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	saved := history.NewHistory(*s.Config.HistoryPath, 10)
+	if err := saved.Load(); err != nil || saved.Count() != 1 {
+		t.Errorf("Expected 1 saved history entry, got %d (%v)", saved.Count(), err)
+	}
+}
+
+// cancelAfter is an io.Writer that cancels a context once it has seen n lines.
+type cancelAfter struct {
+	n      int
+	cancel context.CancelFunc
+	lines  int
+}
+
+// Write counts lines and cancels when the limit is reached.
+func (w *cancelAfter) Write(p []byte) (int, error) {
+	w.lines += bytes.Count(p, []byte("\n"))
+	if w.lines >= w.n {
+		w.cancel()
+	}
+	return len(p), nil
+}
+
+// TestRunBatchCancel tests that cancelling midway stops before the next
+// expression and still saves the results processed so far.
+func TestRunBatchCancel(t *testing.T) {
+	s := newTestService(t, "")
+	path := writeBatchFile(t, "1 + 1", "2 + 2", "3 + 3", "4 + 4", "5 + 5")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var progress bytes.Buffer
+	result, err := s.RunBatch(ctx, path, &cancelAfter{n: 2, cancel: cancel}, &progress)
+	if err != nil {
+		t.Fatalf("RunBatch returned error: %v", err)
+	}
+
+	expected := BatchResult{Total: 5, Processed: 2, Cancelled: true}
+	if result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+	if !strings.Contains(progress.String(), "cancelled after 2/5") {
+		t.Errorf("Expected a cancellation report, got %q", progress.String())
+	}
+
+	// Only the processed entries were recorded, and they reached the file
+	saved := history.NewHistory(*s.Config.HistoryPath, 10)
+	if err := saved.Load(); err != nil {
+		t.Fatalf("Failed to load saved history: %v", err)
+	}
+	entries := saved.GetAll()
+	if len(entries) != 2 || entries[0].Expression != "1 + 1" || entries[1].Expression != "2 + 2" {
+		t.Errorf("Expected the first two expressions saved, got %+v", entries)
+	}
+}
+
+// TestRunBatchMissingFile tests that an unreadable batch file is a FileError.
+func TestRunBatchMissingFile(t *testing.T) {
+	s := newTestService(t, "")
+
+	var out bytes.Buffer
+	_, err := s.RunBatch(context.Background(), filepath.Join(t.TempDir(), "missing.txt"), &out, &out)
+	if err == nil || !strings.Contains(err.Error(), "missing.txt") {
+		t.Errorf("Expected a file error naming missing.txt, got %v", err)
+	}
+}
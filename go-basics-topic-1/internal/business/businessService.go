@@ -9,9 +9,19 @@ import (
 	"cli-calculator/internal/errors"
 	"cli-calculator/internal/history"
 	"cli-calculator/internal/logger"
+	"cli-calculator/internal/system"
 	"cli-calculator/internal/util"
 	"cli-calculator/internal/validation"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Service holds the application state and dependencies.
@@ -19,16 +29,39 @@ import (
 type Service struct {
 	Config  *config.Config  // Application configuration
 	History *history.History // Calculation history
+	Verbose bool             // Print evaluation steps for expressions
+	OutputFormat string      // One-shot output format (constants.OutputFormatText or OutputFormatJSON)
+	CopyResult   bool        // Copy each result to the system clipboard
+
+	startedAt time.Time // When the session started, for the runtime log on exit
+	firstRun  bool      // No config file existed at startup, so Run offers the setup wizard
+
+	lastResult    float64 // Running value for chained operations
+	hasLastResult bool    // Whether lastResult holds a result yet
+
+	previousConfig *config.Config // Settings before the last change, for undo; nil when there is nothing to undo
+	audit          *logger.AuditLog // Compliance log of every calculation; nil when Config.AuditLogPath is empty
+	stopAutoSave   func()           // Stops the menu's auto-save ticker; nil outside Run
 }
 
 // NewService creates a new Service instance with loaded configuration and history.
+// An empty configPath loads the configuration from the default location.
 // This demonstrates constructor functions and initialization.
-func NewService() (*Service, error) {
+func NewService(configPath string) (*Service, error) {
 	// Load configuration
-	cfg, err := config.Load()
+	var cfg *config.Config
+	var err error
+	if configPath != "" {
+		cfg, err = config.LoadFrom(configPath)
+	} else {
+		cfg, err = config.Load()
+	}
 	if err != nil {
 		logger.Error("Failed to load configuration: %v", err)
 		cfg = config.DefaultConfig() // Use defaults on error
+		if configPath != "" {
+			cfg.ConfigPath = &configPath // Keep saving to the requested file
+		}
 	}
 
 	// Initialize history
@@ -41,26 +74,94 @@ func NewService() (*Service, error) {
 	} else {
 		hist = history.NewHistory("", cfg.MaxHistory)
 	}
+	hist.ReadOnly = cfg.HistoryReadOnly
+	hist.Dedup = cfg.DedupHistory
+	hist.MaxBytes = cfg.MaxHistoryBytes
+	util.SetOutputWidth(cfg.OutputWidth)
+	util.DefaultIO().Echo = cfg.EchoInput
+
+	// A missing config file means this is the first run
+	firstRun := false
+	if cfg.ConfigPath != nil {
+		_, statErr := os.Stat(*cfg.ConfigPath)
+		firstRun = os.IsNotExist(statErr)
+	}
+
+	// Open the audit log; a bad path disables auditing rather than the calculator
+	var audit *logger.AuditLog
+	if cfg.AuditLogPath != "" {
+		if audit, err = logger.OpenAuditLog(cfg.AuditLogPath); err != nil {
+			logger.Warn("Audit log disabled: %v", err)
+		}
+	}
 
 	return &Service{
-		Config:  cfg,
-		History: hist,
+		Config:    cfg,
+		History:   hist,
+		startedAt: time.Now(),
+		firstRun:  firstRun,
+		audit:     audit,
 	}, nil
 }
 
+// recordAudit appends a calculation to the audit log, if one is configured.
+// It runs whether or not history is being saved, since the audit log is a
+// record of what was done rather than a convenience for the user.
+func (s *Service) recordAudit(operation, expression string, result float64, calcErr error) {
+	if err := s.audit.Record(operation, expression, result, calcErr); err != nil {
+		logger.Warn("Failed to write audit log: %v", err)
+	}
+}
+
 // Run starts the main application loop.
 // This demonstrates control flow and menu-driven interfaces.
 func (s *Service) Run() error {
+	// Let `kill -HUP` flush history during long sessions
+	stopFlush := system.FlushOnHangup(s.History, func(err error) {
+		if err != nil {
+			logger.Warn("SIGHUP flush failed: %v", err)
+			return
+		}
+		logger.Info("SIGHUP received: history flushed to disk")
+	})
+	defer stopFlush()
+
+	s.stopAutoSave = s.startAutoSave()
+	defer func() {
+		s.stopAutoSave()
+		s.stopAutoSave = nil
+	}()
+
+	// Guide first-time users through setup, unless input is scripted
+	if s.firstRun && system.IsTerminal(os.Stdin) {
+		if err := s.runSetupWizard(); err != nil {
+			logger.Warn("Setup wizard failed, using default settings: %v", err)
+		}
+	}
+
 	// Display welcome message if configured
 	if s.Config.ShowWelcome {
-		util.DisplayWelcome()
+		util.DisplayWelcome(s.Config.BannerText)
+	}
+	if s.History.ReadOnly {
+		util.PrintInfo("History is read-only: calculations won't be recorded or saved")
 	}
 
 	// Main loop
 	for {
 		util.DisplayMainMenu()
 
-		input, err := util.GetUserInput("Enter your choice (1-7): ")
+		input, err := util.GetUserInput(fmt.Sprintf("Enter your choice (%d-%d): ", constants.MinMenuOption, constants.MaxMenuOption))
+		if stderrors.Is(err, errors.ErrOutputClosed) {
+			// Nobody is reading any more (e.g. piped into head): stop quietly
+			logger.Info("Output closed; saving history and exiting")
+			if s.Config.SaveHistory {
+				if saveErr := s.History.Save(); saveErr != nil {
+					logger.Error("Failed to save history: %v", saveErr)
+				}
+			}
+			return nil
+		}
 		if err != nil {
 			return errors.Wrap(err, "failed to read menu input")
 		}
@@ -72,8 +173,8 @@ func (s *Service) Run() error {
 			continue
 		}
 
-		// Handle the menu option
-		shouldExit, err := s.handleMenuOption(option)
+		// Handle the menu option; a panic in it is reported like any other error
+		shouldExit, err := s.handleMenuOptionSafely(option)
 		if err != nil {
 			util.PrintError(err)
 			util.PressEnterToContinue()
@@ -85,6 +186,123 @@ func (s *Service) Run() error {
 	}
 }
 
+// menuHandler handles one main menu choice; tests replace it to simulate a
+// broken handler.
+var menuHandler = (*Service).handleMenuOption
+
+// handleMenuOptionSafely runs the handler for option, recovering from a panic
+// so one broken handler doesn't crash the session and lose unsaved history.
+// The panic and its stack are logged, history is saved straight away, and an
+// error wrapping errors.ErrInternal is returned so the menu loop carries on.
+// This demonstrates defer and recover.
+func (s *Service) handleMenuOptionSafely(option constants.MenuOption) (shouldExit bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Recovered from panic in menu option %d: %v\n%s", option, r, debug.Stack())
+			if s.Config.SaveHistory {
+				if saveErr := s.History.Save(); saveErr != nil {
+					logger.Error("Failed to save history after panic: %v", saveErr)
+				}
+			}
+			shouldExit, err = false, fmt.Errorf("%w: %v", errors.ErrInternal, r)
+		}
+	}()
+
+	return menuHandler(s, option)
+}
+
+// SetPrecision overrides the configured precision (e.g. from the -precision
+// flag) for results printed by the menu, -expr and -batch alike. Values
+// outside the config's allowed range are rejected and leave it unchanged.
+func (s *Service) SetPrecision(n int) error {
+	previous := s.Config.Precision
+	s.Config.Precision = n
+	if err := s.Config.Validate(); err != nil {
+		s.Config.Precision = previous
+		return err
+	}
+	return nil
+}
+
+// SetMaxHistory overrides the configured history size (e.g. from a command-line
+// flag) and trims already-loaded entries to fit. Values outside the config's
+// allowed range are rejected and leave the size unchanged.
+func (s *Service) SetMaxHistory(n int) error {
+	previous := s.Config.MaxHistory
+	s.Config.MaxHistory = n
+	if err := s.Config.Validate(); err != nil {
+		s.Config.MaxHistory = previous
+		return err
+	}
+
+	s.History.SetMaxSize(n)
+	return nil
+}
+
+// runSetupWizard asks for first-run preferences and saves them to the
+// config file so the wizard isn't shown again.
+func (s *Service) runSetupWizard() error {
+	// Start from the current config so command-line flags and paths are kept
+	cfg, err := config.RunWizard(util.DefaultIO(), s.Config)
+	if err != nil {
+		return err
+	}
+
+	s.Config = cfg
+	s.firstRun = false
+	s.applySettings()
+
+	if err := s.Config.Save(); err != nil {
+		return err
+	}
+	util.PrintSuccess(fmt.Sprintf("Settings saved to %s", *s.Config.ConfigPath))
+	return nil
+}
+
+// startAutoSave saves history every Config.AutoSaveInterval seconds in the
+// background when interval auto-save is on. The returned function stops the
+// ticker and saves one final time; it does nothing when the interval is 0.
+func (s *Service) startAutoSave() (stop func()) {
+	if !s.Config.AutoSave || !s.Config.SaveHistory || s.History.ReadOnly || s.Config.AutoSaveInterval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(time.Duration(s.Config.AutoSaveInterval) * time.Second)
+	stopSaving := system.SaveOnTick(s.History, ticker.C, func(err error) {
+		if err != nil {
+			logger.Warn("Failed to save history: %v", err)
+		}
+	})
+	logger.Debug("Saving history every %ds", s.Config.AutoSaveInterval)
+
+	return func() {
+		ticker.Stop()
+		stopSaving()
+	}
+}
+
+// restartAutoSave replaces the running auto-save ticker with one built from
+// the current config, so a changed interval or toggle applies straight away.
+// Outside Run there is no ticker and it does nothing.
+func (s *Service) restartAutoSave() {
+	if s.stopAutoSave == nil {
+		return
+	}
+	s.stopAutoSave()
+	s.stopAutoSave = s.startAutoSave()
+}
+
+// saveAfterCalculation saves history right after a calculation when auto-save
+// is on and no interval is set; with an interval the ticker saves instead.
+func (s *Service) saveAfterCalculation() {
+	if !s.Config.AutoSave || s.History.ReadOnly || s.Config.AutoSaveInterval > 0 {
+		return
+	}
+	if err := s.History.Save(); err != nil {
+		logger.Warn("Failed to save history: %v", err)
+	}
+}
+
 // handleMenuOption processes a menu selection and returns whether to exit.
 func (s *Service) handleMenuOption(option constants.MenuOption) (bool, error) {
 	logger.Debug("Handling menu option: %d", option)
@@ -104,6 +322,8 @@ func (s *Service) handleMenuOption(option constants.MenuOption) (bool, error) {
 		return false, s.handleHelp()
 	case constants.MenuExit:
 		return s.handleExit()
+	case constants.MenuQuickOperations:
+		return false, s.handleQuickOperations()
 	default:
 		return false, errors.NewValidationError("menu_option", fmt.Sprintf("%d", option), "invalid menu option")
 	}
@@ -135,9 +355,11 @@ func (s *Service) handleBasicCalculator() error {
 			continue
 		}
 
-		// Perform calculation
+		// Perform calculation, then offer to chain from its result
 		if err := s.performCalculation(operation); err != nil {
 			util.PrintError(err)
+		} else if err := s.continueCalculation(); err != nil {
+			util.PrintError(err)
 		}
 
 		util.PressEnterToContinue()
@@ -154,7 +376,7 @@ func (s *Service) handleAdvancedCalculator() error {
 	util.DisplayAdvancedCalculatorMenu()
 
 	for {
-		input, err := util.GetUserInput("Enter operation (1-4) or 0 to go back: ")
+		input, err := util.GetUserInput("Enter operation (1-10) or 0 to go back: ")
 		if err != nil {
 			return err
 		}
@@ -164,6 +386,24 @@ func (s *Service) handleAdvancedCalculator() error {
 			return nil
 		}
 
+		// Compound interest has its own four-input flow
+		if input == advancedCompoundInterest {
+			if err := s.handleCompoundInterest(); err != nil {
+				util.PrintError(err)
+			}
+			util.PressEnterToContinue()
+			return nil
+		}
+
+		// Quotient and remainder shows two results
+		if input == advancedDivMod {
+			if err := s.handleDivMod(); err != nil {
+				util.PrintError(err)
+			}
+			util.PressEnterToContinue()
+			return nil
+		}
+
 		// Validate operation
 		operation, err := s.validateAdvancedOperation(input)
 		if err != nil {
@@ -171,9 +411,11 @@ func (s *Service) handleAdvancedCalculator() error {
 			continue
 		}
 
-		// Perform calculation
+		// Perform calculation, then offer to chain from its result
 		if err := s.performCalculation(operation); err != nil {
 			util.PrintError(err)
+		} else if err := s.continueCalculation(); err != nil {
+			util.PrintError(err)
 		}
 
 		util.PressEnterToContinue()
@@ -181,6 +423,64 @@ func (s *Service) handleAdvancedCalculator() error {
 	}
 }
 
+// handleQuickOperations lists the operations pinned in the config and runs
+// the chosen one directly, skipping the basic and advanced submenus.
+func (s *Service) handleQuickOperations() error {
+	if s.Config.ClearScreen {
+		util.ClearScreen()
+	}
+
+	pinned := s.pinnedOperations()
+	if len(pinned) == 0 {
+		util.PrintInfo("No pinned operations. Add names like \"divide\" or \"^\" to pinned_operations in the config file.")
+		util.PressEnterToContinue()
+		return nil
+	}
+
+	util.DisplayQuickMenu(pinned)
+
+	for {
+		input, err := util.GetUserInput(fmt.Sprintf("Enter operation (1-%d) or 0 to go back: ", len(pinned)))
+		if err != nil {
+			return err
+		}
+
+		if input == "0" {
+			return nil
+		}
+
+		choice, err := strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(pinned) {
+			util.PrintError(errors.NewValidationError("operation", input, fmt.Sprintf("must be between 1 and %d", len(pinned))))
+			continue
+		}
+
+		if err := s.performCalculation(pinned[choice-1]); err != nil {
+			util.PrintError(err)
+		} else if err := s.continueCalculation(); err != nil {
+			util.PrintError(err)
+		}
+
+		util.PressEnterToContinue()
+		return nil
+	}
+}
+
+// pinnedOperations resolves Config.PinnedOperations to operations. Validate
+// rejects unknown names on load, so any left (e.g. set in code) are skipped.
+func (s *Service) pinnedOperations() []constants.Operation {
+	var operations []constants.Operation
+	for _, name := range s.Config.PinnedOperations {
+		op, ok := constants.LookupOperation(name)
+		if !ok {
+			logger.Warn("Ignoring unknown pinned operation %q", name)
+			continue
+		}
+		operations = append(operations, op)
+	}
+	return operations
+}
+
 // validateAdvancedOperation validates advanced calculator input.
 func (s *Service) validateAdvancedOperation(input string) (constants.Operation, error) {
 	// Parse input
@@ -196,11 +496,15 @@ func (s *Service) validateAdvancedOperation(input string) (constants.Operation,
 		2: constants.OpSquareRoot,
 		3: constants.OpModulo,
 		4: constants.OpFactorial,
+		5: constants.OpPercentChange,
+		6: constants.OpRatio,
+		8: constants.OpRound, // 7 is compound interest, handled before this
+		9: constants.OpAverage, // 10 is quotient and remainder, also handled before this
 	}
 
 	op, ok := operations[num]
 	if !ok {
-		return 0, errors.NewValidationError("operation", input, "must be between 1 and 4")
+		return 0, errors.NewValidationError("operation", input, "must be between 1 and 10")
 	}
 
 	return op, nil
@@ -214,51 +518,289 @@ func (s *Service) performCalculation(operation constants.Operation) error {
 		return err
 	}
 
+	return s.calculateAndRecord(operation, operands)
+}
+
+// calculateAndRecord computes, displays, and records one calculation, and
+// remembers its result so the next operation can chain from it.
+func (s *Service) calculateAndRecord(operation constants.Operation, operands []float64) error {
+	// Large factorials can be slow on old machines, so ask first; saying no
+	// is the user's choice, not an error, so nothing is shown or recorded
+	if operation == constants.OpFactorial && len(operands) == 1 {
+		ok, err := s.confirmLargeFactorial(operands[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			util.PrintInfo(fmt.Sprintf("%g! not calculated", operands[0]))
+			return nil
+		}
+	}
+
 	// Build expression string
 	expression := s.buildExpression(operation, operands)
 
-	// Perform calculation
-	result, err := calculator.Calculate(operation, operands)
+	// Perform calculation, timing it for the debug log
+	start := time.Now()
+	calcResult, err := calculator.CalculateWithOptions(operation, operands, s.calcOptions())
+	elapsed := time.Since(start)
+	logger.Debug("%s took %dµs", operation.String(), elapsed.Microseconds())
+	s.recordAudit(operation.String(), expression, calcResult.Value, err)
 	if err != nil {
 		// Record failure in history
 		if s.Config.SaveHistory {
-			s.History.AddError(operation.String(), expression, err)
+			s.History.Add(history.Entry{
+				Operation:  operation.String(),
+				Expression: expression,
+				Success:    false,
+				Error:      err.Error(),
+				Duration:   elapsed,
+			})
 		}
 		return err
 	}
+	result := calcResult.Value
+	s.lastResult, s.hasLastResult = result, true
 
 	// Format result
-	resultStr := calculator.FormatResult(result, s.Config.Precision)
+	resultStr := s.renderResult(calcResult)
+	if !calcResult.Exact {
+		logger.Debug("Result of %s may include floating-point rounding", expression)
+	}
 
 	// Display result
-	util.PrintResult(operation.String(), expression, resultStr)
+	util.PrintResultWithWords(calcResult.Operation.String(), expression, resultStr, s.spokenResult(calcResult))
+	s.copyResult(s.formatCalculation(calcResult))
 
-	// Add to history
+	// Add to history, storing the result as displayed rather than with float noise
 	if s.Config.SaveHistory {
-		s.History.AddSuccess(operation.String(), expression, result)
+		stored := calculator.RoundTo(result, s.Config.PrecisionFor(operation))
+
+		// Warn about repeated calculations before recording them
+		candidate := history.Entry{Operation: operation.String(), Expression: expression, Result: stored}
+		if s.History.ContainsSimilar(candidate, constants.DefaultEpsilon) {
+			util.PrintWarning("You have already performed this calculation")
+		}
+
+		s.History.Add(history.Entry{
+			Operation:  operation.String(),
+			Expression: expression,
+			Result:     stored,
+			Success:    true,
+			Duration:   elapsed,
+			Label:      s.promptLabel(),
+		})
 
 		// Auto-save history if configured
-		if s.Config.AutoSave {
-			if err := s.History.Save(); err != nil {
-				logger.Warn("Failed to save history: %v", err)
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("Calculation completed: %s = %s", expression, resultStr)
+	return nil
+}
+
+// renderResult formats a calculation result for display: a percent sign for
+// percent changes, the reduced ratio for ratios (2:3 (0.67)), and otherwise
+// the fraction when show_fraction is on.
+func (s *Service) renderResult(r calculator.Result) string {
+	resultStr := s.formatCalculation(r)
+	switch r.Operation {
+	case constants.OpPercentChange:
+		resultStr += "%"
+	case constants.OpRatio:
+		if ratio, err := calculator.Ratio(r.Operands[0], r.Operands[1]); err == nil {
+			resultStr = fmt.Sprintf("%s (%s)", ratio, resultStr)
+		}
+	default:
+		if fraction := s.fractionOf(r.Value); fraction != "" {
+			resultStr = fmt.Sprintf("%s (%s)", resultStr, fraction)
+		}
+	}
+	return resultStr
+}
+
+// promptLabel asks for an optional note to attach to the calculation just
+// recorded when Config.PromptForLabel is set. Empty input means no label.
+// A failed read only loses the label, not the calculation.
+func (s *Service) promptLabel() string {
+	if !s.Config.PromptForLabel || s.History.ReadOnly {
+		return ""
+	}
+
+	label, err := util.GetUserInput("Label (optional, press Enter to skip): ")
+	if err != nil {
+		logger.Warn("Failed to read label: %v", err)
+		return ""
+	}
+	return label
+}
+
+// copyToClipboard copies text to the clipboard; tests replace it to avoid
+// touching the real clipboard.
+var copyToClipboard = system.CopyToClipboard
+
+// copyResult copies a formatted result to the clipboard when CopyResult is
+// set. Failing to copy (e.g. no clipboard tool) only logs a warning, since
+// the calculation itself succeeded.
+func (s *Service) copyResult(formatted string) {
+	if !s.CopyResult {
+		return
+	}
+	if err := copyToClipboard(formatted); err != nil {
+		logger.Warn("Failed to copy result to clipboard: %v", err)
+		return
+	}
+	logger.Debug("Copied %s to clipboard", formatted)
+}
+
+// expressionOutput is the JSON shape of a one-shot expression result.
+type expressionOutput struct {
+	Expression string   `json:"expression"`
+	Result     float64  `json:"result"`
+	Formatted  string   `json:"formatted"`
+	Steps      []string `json:"steps,omitempty"`
+}
+
+// EvaluateExpression evaluates a single infix expression, prints the result,
+// and records it in history. When Verbose is set, each reduction step is
+// printed first so learners can follow the evaluation order. With JSON output
+// both results and errors are printed as JSON.
+func (s *Service) EvaluateExpression(expr string) error {
+	evaluated, steps, err := calculator.EvaluateResult(expr, s.calcOptions())
+	result := evaluated.Value
+	s.recordAudit("Expression", expr, result, err)
+	if err != nil {
+		if s.Config.SaveHistory {
+			s.History.AddError("Expression", expr, err)
+		}
+		if s.OutputFormat == constants.OutputFormatJSON {
+			if jsonErr := util.PrintErrorJSON(err); jsonErr != nil {
+				logger.Error("Failed to write JSON error: %v", jsonErr)
 			}
 		}
+		return err
 	}
 
-	logger.Info("Calculation completed: %s = %s", expression, resultStr)
+	resultStr := s.formatCalculation(evaluated)
+
+	if s.OutputFormat == constants.OutputFormatJSON {
+		output := expressionOutput{Expression: expr, Result: result, Formatted: resultStr}
+		if s.Verbose {
+			output.Steps = steps
+		}
+		if err := util.PrintJSON(output); err != nil {
+			return err
+		}
+	} else {
+		out := util.DefaultIO().Out
+		if s.Verbose {
+			fmt.Fprintln(out, "Evaluation steps:")
+			for i, step := range steps {
+				fmt.Fprintf(out, "  %d. %s\n", i+1, step)
+			}
+		}
+		fmt.Fprintf(out, "%s = %s\n", expr, resultStr)
+	}
+	s.copyResult(resultStr)
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess("Expression", expr, calculator.RoundTo(result, s.Config.Precision))
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("Expression evaluated: %s = %s", expr, resultStr)
 	return nil
 }
 
+// WriteReport writes the history statistics report to the file at path.
+func (s *Service) WriteReport(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.NewFileError(path, "create", err)
+	}
+
+	if err := s.History.WriteStatisticsReport(file); err != nil {
+		file.Close()
+		return errors.NewFileError(path, "write", err)
+	}
+	if err := file.Close(); err != nil {
+		return errors.NewFileError(path, "close", err)
+	}
+
+	logger.Info("Statistics report written to %s", path)
+	return nil
+}
+
+// WriteStatistics writes the history statistics to w, as the text report or,
+// when OutputFormat is JSON, as a single JSON object.
+func (s *Service) WriteStatistics(w io.Writer) error {
+	if s.OutputFormat != constants.OutputFormatJSON {
+		return s.History.WriteStatisticsReport(w)
+	}
+
+	data, err := json.Marshal(s.History.GetStatistics())
+	if err != nil {
+		return errors.WrapWithContext(err, "failed to marshal statistics")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// confirmLargeFactorial reports whether to go ahead with n!. Inputs above
+// Config.FactorialConfirmAbove print a warning and ask, defaulting to no;
+// smaller ones, and ones factorial rejects anyway, need no confirmation.
+func (s *Service) confirmLargeFactorial(n float64) (bool, error) {
+	threshold := s.Config.FactorialConfirmAbove
+	if threshold <= 0 || n <= float64(threshold) || n > float64(s.calcOptions().MaxFactorialInput) {
+		return true, nil
+	}
+
+	util.PrintWarning(fmt.Sprintf("%g! has %d digits and may take a while on slow machines.", n, factorialDigits(n)))
+	ok, err := util.ConfirmWithDefault("Calculate it anyway?", false)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		logger.Info("Factorial of %g cancelled by user", n)
+	}
+	return ok, nil
+}
+
+// factorialDigits returns the number of decimal digits in n!, using
+// log10(n!) = lgamma(n+1) / ln(10) so the factorial itself isn't computed.
+func factorialDigits(n float64) int {
+	lg, _ := math.Lgamma(n + 1)
+	return int(math.Floor(lg/math.Ln10)) + 1
+}
+
 // getOperands prompts for and collects operands based on operation type.
 func (s *Service) getOperands(operation constants.Operation) ([]float64, error) {
 	switch operation {
-	case constants.OpSquareRoot, constants.OpFactorial:
+	case constants.OpSquareRoot:
 		// Single operand operations
 		num, err := s.readNumber("Enter number: ")
 		if err != nil {
 			return nil, err
 		}
 		return []float64{num}, nil
+	case constants.OpFactorial:
+		n, err := s.readInteger("Enter number: ")
+		if err != nil {
+			return nil, err
+		}
+		return []float64{n}, nil
+	case constants.OpRound:
+		value, err := s.readNumber("Enter number: ")
+		if err != nil {
+			return nil, err
+		}
+		decimals, err := s.readInteger("Enter decimal places: ")
+		if err != nil {
+			return nil, err
+		}
+		return []float64{value, decimals}, nil
+	case constants.OpAverage:
+		return s.readNumberList("Enter numbers separated by spaces: ")
 	default:
 		// Binary operations
 		a, err := s.readNumber("Enter first number: ")
@@ -274,33 +816,216 @@ func (s *Service) getOperands(operation constants.Operation) ([]float64, error)
 }
 
 // readNumber prompts for and validates a number input.
+// Invalid values are re-prompted up to Config.MaxInputRetries attempts;
+// read errors (such as EOF) end the prompt immediately.
 func (s *Service) readNumber(prompt string) (float64, error) {
-	input, err := util.GetUserInput(prompt)
+	var num float64
+	err := s.readValue(prompt, func(input string) (err error) {
+		opts := s.calcOptions()
+		num, err = validation.ValidateNumberInRange(input, s.Config.DecimalSeparator, opts.MinOperand, opts.MaxOperand)
+		return err
+	})
+	return num, err
+}
+
+// readInteger prompts for a whole number for integer-only operations, so a
+// fraction is rejected at the prompt with a clear message. Operand limits
+// and retries work as in readNumber.
+func (s *Service) readInteger(prompt string) (float64, error) {
+	var num int64
+	err := s.readValue(prompt, func(input string) (err error) {
+		opts := s.calcOptions()
+		num, err = validation.ValidateIntegerInRange(input, opts.MinOperand, opts.MaxOperand)
+		return err
+	})
+	return float64(num), err
+}
+
+// readNumberList prompts for one or more numbers on a single line for
+// variadic operations like average. Numbers are separated by spaces, or also
+// by commas when the decimal separator isn't a comma. Retries work as in
+// readNumber, and one bad number re-prompts for the whole line.
+func (s *Service) readNumberList(prompt string) ([]float64, error) {
+	var numbers []float64
+	err := s.readValue(prompt, func(input string) error {
+		if s.Config.DecimalSeparator != "," {
+			input = strings.ReplaceAll(input, ",", " ")
+		}
+		fields := strings.Fields(input)
+		if len(fields) == 0 {
+			return errors.NewValidationError("numbers", input, "at least one number is required")
+		}
+
+		opts := s.calcOptions()
+		numbers = make([]float64, len(fields))
+		for i, field := range fields {
+			num, err := validation.ValidateNumberInRange(field, s.Config.DecimalSeparator, opts.MinOperand, opts.MaxOperand)
+			if err != nil {
+				return err
+			}
+			numbers[i] = num
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	return numbers, nil
+}
+
+// readValue prompts until parse accepts the input or the attempts run out.
+// parse stores the parsed value itself, so one loop serves every kind of input.
+func (s *Service) readValue(prompt string, parse func(string) error) error {
+	attempts := s.Config.MaxInputRetries
+	if attempts < 1 {
+		attempts = 1
 	}
 
-	return validation.ValidateNumber(input)
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		input, err := util.GetUserInput(prompt)
+		if err != nil {
+			return err
+		}
+
+		err = parse(input)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if attempt < attempts {
+			util.PrintError(err)
+			util.PrintInfo(fmt.Sprintf("Please try again (attempt %d of %d)", attempt+1, attempts))
+		}
+	}
+
+	return lastErr
+}
+
+// calcOptions builds calculator limits from the configuration.
+func (s *Service) calcOptions() calculator.Options {
+	opts := calculator.DefaultOptions()
+	if s.Config.MaxOperand > 0 {
+		opts.MaxOperand = s.Config.MaxOperand
+	}
+	if s.Config.MinOperand > opts.MinOperand {
+		opts.MinOperand = s.Config.MinOperand
+	}
+	if s.Config.MaxFactorialInput > 0 {
+		opts.MaxFactorialInput = s.Config.MaxFactorialInput
+	}
+	if s.Config.ModuloMode != "" {
+		opts.ModuloMode = s.Config.ModuloMode
+	}
+	return opts
+}
+
+// spokenResult spells out an exact integer result when SpokenOutput is enabled.
+// Other results return "" so no words are printed.
+func (s *Service) spokenResult(r calculator.Result) string {
+	if !s.Config.SpokenOutput || !r.Exact {
+		return ""
+	}
+	return util.NumberToWords(int64(r.Value))
+}
+
+// fractionOf returns value as a fraction such as "3/4" when
+// Config.ShowFraction is set, or "" when it isn't, when value is a whole
+// number, or when no fraction within Config.MaxFractionDenominator is close.
+func (s *Service) fractionOf(value float64) string {
+	if !s.Config.ShowFraction {
+		return ""
+	}
+	num, den, ok := calculator.ToFraction(value, s.Config.MaxFractionDenominator)
+	if !ok || den == 1 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", num, den)
+}
+
+// formatResult formats a value using the configured precision, decimal
+// separator, and trailing-zero trimming.
+func (s *Service) formatResult(value float64) string {
+	return s.formatWithPrecision(value, s.Config.Precision)
+}
+
+// formatCalculation formats the result of a menu calculation using its
+// operation's precision override from Config.OperationPrecision, if any.
+// Whole numbers computed exactly from whole numbers print without decimals
+// (4 * 5 = 20) unless Config.ForceDecimals is set.
+func (s *Service) formatCalculation(r calculator.Result) string {
+	opts := s.formatOptions(s.Config.PrecisionFor(r.Operation))
+	opts.WholeNumber = r.Exact && !s.Config.ForceDecimals
+	return calculator.FormatResultWith(r.Value, opts)
+}
+
+// formatWithPrecision formats value with the configured separator and
+// trimming at the given number of decimal places.
+func (s *Service) formatWithPrecision(value float64, precision int) string {
+	return calculator.FormatResultWith(value, s.formatOptions(precision))
+}
+
+// formatOptions returns the configured formatting at the given precision.
+func (s *Service) formatOptions(precision int) calculator.FormatOptions {
+	return calculator.FormatOptions{
+		Precision:         precision,
+		DecimalSeparator:  s.Config.DecimalSeparator,
+		TrimTrailingZeros: s.Config.TrimTrailingZeros,
+	}
 }
 
-// buildExpression builds a human-readable expression string.
+// buildExpression builds a human-readable expression string, showing
+// operands at two decimals (e.g. "5.00 + 3.00").
 func (s *Service) buildExpression(operation constants.Operation, operands []float64) string {
+	return formatExpression(operation, operands, func(v float64) string { return fmt.Sprintf("%.2f", v) })
+}
+
+// formatExpression builds an expression string with number rendering each
+// operand. Factorials, roundings, and averages always show operands in
+// full, since showing them at display precision would hide what was computed.
+func formatExpression(operation constants.Operation, operands []float64, number func(float64) string) string {
 	switch operation {
 	case constants.OpSquareRoot:
-		return fmt.Sprintf("√%.2f", operands[0])
+		return "√" + number(operands[0])
 	case constants.OpFactorial:
-		return fmt.Sprintf("%.0f!", operands[0])
+		return plainNumber(operands[0]) + "!"
+	case constants.OpPercentChange:
+		return number(operands[0]) + " → " + number(operands[1])
+	case constants.OpRatio:
+		return number(operands[0]) + ":" + number(operands[1])
+	case constants.OpRound:
+		// Full digits, since showing the input at display precision would hide the rounding
+		return fmt.Sprintf("round(%s, %s)", plainNumber(operands[0]), plainNumber(operands[1]))
+	case constants.OpAverage:
+		numbers := make([]string, len(operands))
+		for i, operand := range operands {
+			numbers[i] = plainNumber(operand)
+		}
+		return fmt.Sprintf("avg(%s)", strings.Join(numbers, ", "))
 	case constants.OpAddition, constants.OpSubtraction, constants.OpMultiplication, constants.OpDivision, constants.OpPower, constants.OpModulo:
 		if len(operands) >= 2 {
-			return fmt.Sprintf("%.2f %s %.2f", operands[0], operation.Symbol(), operands[1])
+			// Arithmetic folds left over any number of operands (e.g. from -- add 1 2 3)
+			terms := make([]string, len(operands))
+			for i, operand := range operands {
+				terms[i] = number(operand)
+			}
+			return strings.Join(terms, " "+operation.Symbol()+" ")
 		}
 	}
 	return fmt.Sprintf("%s(%v)", operation.String(), operands)
 }
 
-// handleBatchCalculations handles batch calculation mode (placeholder).
+// handleBatchCalculations runs a what-if sweep over one operand.
 func (s *Service) handleBatchCalculations() error {
-	util.PrintInfo("Batch calculations feature coming soon!")
+	if s.Config.ClearScreen {
+		util.ClearScreen()
+	}
+
+	if err := s.runSweep(os.Stdout); err != nil {
+		return err
+	}
+
 	util.PressEnterToContinue()
 	return nil
 }
@@ -311,7 +1036,11 @@ func (s *Service) handleHistory() error {
 		util.ClearScreen()
 	}
 
-	fmt.Println("CALCULATION HISTORY:")
+	if s.History.ReadOnly {
+		fmt.Println("CALCULATION HISTORY (read-only):")
+	} else {
+		fmt.Println("CALCULATION HISTORY:")
+	}
 	util.PrintDivider()
 
 	entries := s.History.GetAll()
@@ -323,12 +1052,24 @@ func (s *Service) handleHistory() error {
 			if !entry.Success {
 				status = "✗"
 			}
-			fmt.Printf("%d. [%s] %s: %s = ", i+1, status, entry.Timestamp.Format("15:04:05"), entry.Expression)
+			expression := util.Truncate(entry.Expression, s.Config.MaxExpressionDisplay)
+			fmt.Printf("%d. [%s] %s: %s = ", i+1, status, s.formatTimestamp(entry.Timestamp), expression)
 			if entry.Success {
-				fmt.Printf("%.2f\n", entry.Result)
+				fmt.Print(s.formatEntryResult(entry))
 			} else {
-				fmt.Printf("Error: %s\n", entry.Error)
+				fmt.Printf("Error: %s", entry.Error)
+			}
+			if entry.Times() > 1 {
+				fmt.Printf(" ×%d", entry.Times())
+			}
+			// Entries recorded before timing was added have no duration
+			if entry.Duration > 0 {
+				fmt.Printf(" (%v)", entry.Duration)
+			}
+			if entry.Label != "" {
+				fmt.Printf(" — %s", entry.Label)
 			}
+			fmt.Println()
 		}
 
 		// Display statistics
@@ -347,21 +1088,214 @@ func (s *Service) handleHistory() error {
 	return nil
 }
 
-// handleSettings handles the settings menu (placeholder).
+// formatEntryResult formats a history entry's result like the menu showed
+// it, using its operation's precision, the decimal separator, and trimming.
+func (s *Service) formatEntryResult(entry history.Entry) string {
+	op, _ := constants.LookupOperation(entry.Operation)
+	return s.formatCalculation(calculator.Result{Value: entry.Result, Operation: op})
+}
+
+// formatTimestamp renders a history timestamp with the configured layout,
+// or relative to now (e.g. "2 minutes ago") when RelativeTimestamps is set.
+func (s *Service) formatTimestamp(t time.Time) string {
+	if s.Config.RelativeTimestamps {
+		return util.RelativeTime(t, time.Now())
+	}
+
+	layout := s.Config.TimeFormat
+	if layout == "" {
+		layout = constants.DefaultTimeFormat
+	}
+	return t.Format(layout)
+}
+
+// handleSettings shows the settings menu and applies each change (saving the
+// config when auto-save is on) until the user enters 0 to go back.
 func (s *Service) handleSettings() error {
 	if s.Config.ClearScreen {
 		util.ClearScreen()
 	}
 
-	fmt.Println("SETTINGS:")
-	util.PrintDivider()
-	fmt.Printf("1. Precision: %d decimal places\n", s.Config.Precision)
-	fmt.Printf("2. Save History: %v\n", s.Config.SaveHistory)
-	fmt.Printf("3. Auto-save: %v\n", s.Config.AutoSave)
-	fmt.Printf("4. Clear Screen: %v\n", s.Config.ClearScreen)
-	util.PrintDivider()
-	util.PrintInfo("Settings modification feature coming soon!")
-	util.PressEnterToContinue()
+	for {
+		fmt.Println("SETTINGS:")
+		util.PrintDivider()
+		fmt.Printf("1. Precision: %d decimal places\n", s.Config.Precision)
+		fmt.Printf("2. Save History: %v\n", s.Config.SaveHistory)
+		fmt.Printf("3. Auto-save: %v\n", s.Config.AutoSave)
+		fmt.Printf("4. Clear Screen: %v\n", s.Config.ClearScreen)
+		fmt.Println("5. Edit config file in $EDITOR")
+		fmt.Println("6. Undo last change")
+		util.PrintDivider()
+
+		input, err := util.GetUserInput("Select a setting to change (1-6) or 0 to go back: ")
+		if err != nil {
+			return err
+		}
+
+		if input == "0" {
+			return nil
+		}
+
+		// Editing replaces the whole config, so it skips the save below
+		if input == "5" {
+			if err := s.editConfig(); err != nil {
+				util.PrintError(err)
+			}
+			continue
+		}
+
+		if input == "6" {
+			if err := s.undoSettingChange(); err != nil {
+				util.PrintError(err)
+			}
+			continue
+		}
+
+		// Snapshot first so a successful change can be undone
+		snapshot := s.Config.Clone()
+		if err := s.changeSetting(input); err != nil {
+			util.PrintError(err)
+			continue
+		}
+		s.previousConfig = snapshot
+
+		// Persist the change when configured to
+		if s.Config.AutoSave {
+			if err := s.Config.Save(); err != nil {
+				logger.Warn("Failed to save config: %v", err)
+			}
+		}
+		util.PrintSuccess("Setting updated")
+	}
+}
+
+// editConfig opens the config file in the user's editor and reloads it
+// afterwards. If the edited file is invalid, the current settings are kept.
+func (s *Service) editConfig() error {
+	if s.Config.ConfigPath == nil {
+		return errors.Wrap(errors.ErrConfigInvalid, "config path is nil")
+	}
+	path := *s.Config.ConfigPath
+
+	// Write the current settings first so the editor opens an up-to-date file
+	if err := s.Config.Save(); err != nil {
+		return err
+	}
+
+	if err := system.OpenEditor(path); err != nil {
+		if stderrors.Is(err, errors.ErrNoEditor) {
+			util.PrintInfo(fmt.Sprintf("No editor found. Set $EDITOR (e.g. export EDITOR=nano) or edit %s directly.", path))
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := config.LoadFrom(path)
+	if err != nil {
+		return errors.WrapWithContext(err, "keeping current settings")
+	}
+
+	s.previousConfig = s.Config.Clone()
+	s.Config = cfg
+	s.applySettings()
+	logger.Info("Configuration reloaded from %s", path)
+	util.PrintSuccess("Configuration reloaded")
+	return nil
+}
+
+// undoSettingChange restores the settings from before the last change made
+// in the settings menu and saves them. Only one level of undo is kept.
+func (s *Service) undoSettingChange() error {
+	if s.previousConfig == nil {
+		util.PrintInfo("Nothing to undo")
+		return nil
+	}
+
+	s.Config = s.previousConfig
+	s.previousConfig = nil
+	s.applySettings()
+
+	if err := s.Config.Save(); err != nil {
+		return err
+	}
+	logger.Info("Last setting change undone")
+	util.PrintSuccess("Last change undone")
+	return nil
+}
+
+// applySettings brings the history, auto-save, output width, and input echo
+// in line with the current config after the whole config has been replaced.
+// History goes through its locked setters since auto-save may be saving it.
+func (s *Service) applySettings() {
+	s.History.SetMaxSize(s.Config.MaxHistory)
+	s.History.SetReadOnly(s.Config.HistoryReadOnly)
+	s.History.SetDedup(s.Config.DedupHistory)
+	s.History.SetMaxBytes(s.Config.MaxHistoryBytes)
+	s.restartAutoSave()
+	util.SetOutputWidth(s.Config.OutputWidth)
+	util.DefaultIO().Echo = s.Config.EchoInput
+}
+
+// changeSetting applies the settings-menu choice in input.
+// Boolean settings are toggled; precision prompts for a new value.
+func (s *Service) changeSetting(input string) error {
+	switch input {
+	case "1":
+		value, err := util.GetUserInput("Enter precision (0-15): ")
+		if err != nil {
+			return err
+		}
+		precision, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.NewValidationError("precision", value, "must be a whole number")
+		}
+		if err := validation.ValidatePrecision(precision); err != nil {
+			return err
+		}
+		s.Config.Precision = precision
+	case "2":
+		if err := s.toggleHistory(); err != nil {
+			return err
+		}
+		s.restartAutoSave()
+	case "3":
+		s.Config.AutoSave = !s.Config.AutoSave
+		s.restartAutoSave()
+	case "4":
+		s.Config.ClearScreen = !s.Config.ClearScreen
+	default:
+		return errors.NewValidationError("setting", input, "must be between 1 and 6")
+	}
+
+	logger.Debug("Setting %s changed", input)
+	return nil
+}
+
+// toggleHistory turns history recording on or off. When turning it off, the
+// user may also purge the entries already recorded.
+func (s *Service) toggleHistory() error {
+	if !s.Config.SaveHistory {
+		s.Config.SaveHistory = true
+		logger.Info("History recording resumed")
+		return nil
+	}
+
+	purge, err := util.Confirm("Also clear existing history entries?")
+	if err != nil {
+		return err
+	}
+
+	s.Config.SaveHistory = false
+	if purge {
+		s.History.Clear()
+		if s.Config.AutoSave {
+			if err := s.History.Save(); err != nil {
+				logger.Warn("Failed to save history: %v", err)
+			}
+		}
+		logger.Info("History cleared")
+	}
+	logger.Info("History recording disabled")
 	return nil
 }
 
@@ -378,9 +1312,9 @@ func (s *Service) handleHelp() error {
 
 // handleExit handles application exit.
 func (s *Service) handleExit() (bool, error) {
-	// Confirm exit if configured
-	if s.Config.ConfirmExit {
-		confirm, err := util.Confirm("Are you sure you want to exit?")
+	// Confirm exit if configured, by default only when there is unsaved history
+	if s.Config.ConfirmExit && (!s.Config.ConfirmExitUnsavedOnly || s.History.Dirty()) {
+		confirm, err := util.ConfirmWithDefault("Are you sure you want to exit?", s.Config.ExitByDefault)
 		if err != nil {
 			return false, err
 		}
@@ -396,6 +1330,10 @@ func (s *Service) handleExit() (bool, error) {
 		}
 	}
 
+	if !s.startedAt.IsZero() {
+		logger.Info("Session runtime: %v", time.Since(s.startedAt).Round(time.Second))
+	}
+
 	fmt.Println("\nThank you for using CLI Calculator!")
 	return true, nil
 }
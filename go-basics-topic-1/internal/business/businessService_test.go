@@ -0,0 +1,1266 @@
+// Package businessService provides the application workflows with tests.
+// This demonstrates driving interactive code with scripted input.
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/config"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/history"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestService creates a Service whose files live in a temp directory and
+// whose input comes from the given script (one answer per line).
+func newTestService(t *testing.T, input string) *Service {
+	t.Helper()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	historyPath := filepath.Join(dir, "history.json")
+
+	cfg := config.DefaultConfig()
+	cfg.ConfigPath = &configPath
+	cfg.HistoryPath = &historyPath
+	cfg.ClearScreen = false
+
+	previous := util.SetDefaultIO(util.NewIO(strings.NewReader(input), io.Discard))
+	t.Cleanup(func() { util.SetDefaultIO(previous) })
+
+	return &Service{
+		Config:  cfg,
+		History: history.NewHistory(historyPath, cfg.MaxHistory),
+	}
+}
+
+// captureLogs routes the default logger into a buffer at debug level.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger.GetDefaultLogger().SetOutput(&buf)
+	logger.SetLevel(constants.LogLevelDebug)
+	t.Cleanup(func() {
+		logger.GetDefaultLogger().SetOutput(os.Stdout)
+		logger.SetLevel(constants.LogLevelInfo)
+	})
+
+	return &buf
+}
+
+// TestPerformCalculationLogsTiming tests that each calculation's duration is logged.
+func TestPerformCalculationLogsTiming(t *testing.T) {
+	s := newTestService(t, "5\n3\n")
+	logs := captureLogs(t)
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation returned error: %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "[DEBUG] Addition took ") {
+		t.Errorf("Expected timing debug line, got logs:\n%s", logs.String())
+	}
+}
+
+// TestFormatEntryResult tests that history results use the same precision,
+// separator, and trimming as the menu rather than a fixed two decimals.
+func TestFormatEntryResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		result    float64
+		separator string
+		trim      bool
+		expected  string
+	}{
+		{"default precision", "Addition", 8, ".", false, "8.00"},
+		{"operation precision", "Division", 1.0 / 3, ".", false, "0.3333"},
+		{"decimal comma", "Addition", 2.5, ",", false, "2,50"},
+		{"trimmed zeros", "Addition", 2.5, ".", true, "2.5"},
+		{"unknown operation", "Expression", 1.234, ".", false, "1.23"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.OperationPrecision = map[string]int{"divide": 4}
+			s.Config.DecimalSeparator = tt.separator
+			s.Config.TrimTrailingZeros = tt.trim
+
+			result := s.formatEntryResult(history.Entry{Operation: tt.operation, Result: tt.result, Success: true})
+			if result != tt.expected {
+				t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestFormatTimestamp tests rendering history timestamps with the configured layout.
+func TestFormatTimestamp(t *testing.T) {
+	stamp := time.Date(2024, 3, 15, 9, 5, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		layout   string
+		expected string
+	}{
+		{"default layout", constants.DefaultTimeFormat, "09:05:30"},
+		{"custom layout", "2006-01-02 15:04", "2024-03-15 09:05"},
+		{"unset falls back to default", "", "09:05:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.TimeFormat = tt.layout
+
+			result := s.formatTimestamp(stamp)
+			if result != tt.expected {
+				t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestFormatTimestampRelative tests that RelativeTimestamps replaces the layout.
+func TestFormatTimestampRelative(t *testing.T) {
+	s := newTestService(t, "")
+	s.Config.RelativeTimestamps = true
+
+	// Allow for the clock moving on while the test runs
+	result := s.formatTimestamp(time.Now().Add(-2*time.Minute - time.Second))
+	if result != "2 minutes ago" {
+		t.Errorf("Expected '2 minutes ago', got '%s'", result)
+	}
+}
+
+// TestPerformCalculationRespectsMaxOperand tests that a config-lowered cap is enforced.
+func TestPerformCalculationRespectsMaxOperand(t *testing.T) {
+	s := newTestService(t, "5000\n1\n")
+	s.Config.MaxOperand = 1000
+
+	if err := s.performCalculation(constants.OpAddition); err == nil {
+		t.Error("Expected operand above the configured cap to be rejected")
+	}
+}
+
+// TestReadNumberRetries tests that invalid input is re-prompted until the retry limit.
+func TestReadNumberRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		retries  int
+		expected float64
+		hasError bool
+	}{
+		{"good value after two bad ones", "abc\n1..2\n42\n", 3, 42, false},
+		{"retries exhausted", "abc\n1..2\n42\n", 2, 0, true},
+		{"first value good", "7\n", 1, 7, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.MaxInputRetries = tt.retries
+
+			result, err := s.readNumber("Enter number: ")
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %v", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestPerformCalculationUsesRetriedValue tests that a calculation completes after bad input.
+func TestPerformCalculationUsesRetriedValue(t *testing.T) {
+	s := newTestService(t, "x\ny\n5\n3\n")
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+
+	entries := s.History.GetAll()
+	if len(entries) != 1 || entries[0].Result != 8 {
+		t.Errorf("Expected one entry with result 8, got %+v", entries)
+	}
+}
+
+// TestPerformCalculationRound tests the round operation's prompts and the
+// expression it records.
+func TestPerformCalculationRound(t *testing.T) {
+	s := newTestService(t, "3.14159\n2\n")
+
+	if err := s.performCalculation(constants.OpRound); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+
+	entries := s.History.GetAll()
+	if len(entries) != 1 || entries[0].Result != 3.14 || entries[0].Expression != "round(3.14159, 2)" {
+		t.Errorf("Expected round(3.14159, 2) = 3.14, got %+v", entries)
+	}
+}
+
+// TestPerformCalculationFactorialRejectsFraction tests that a fractional
+// factorial operand is re-prompted before reaching the calculator.
+func TestPerformCalculationFactorialRejectsFraction(t *testing.T) {
+	s := newTestService(t, "5.5\n5\n")
+
+	if err := s.performCalculation(constants.OpFactorial); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+
+	entries := s.History.GetAll()
+	if len(entries) != 1 || !entries[0].Success || entries[0].Result != 120 {
+		t.Errorf("Expected one successful entry with result 120, got %+v", entries)
+	}
+}
+
+// TestPerformCalculationOperationPrecision tests that an operation with a
+// precision override uses it while others keep the default precision.
+func TestPerformCalculationOperationPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		input     string
+		stored    float64
+		formatted string
+	}{
+		{"division uses its override", constants.OpDivision, "10\n3\n", 3.3333, "3.3333"},
+		{"addition uses the default", constants.OpAddition, "1.2345\n1\n", 2.23, "2.23"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.Precision = 2
+			s.Config.OperationPrecision = map[string]int{"divide": 4}
+
+			if err := s.performCalculation(tt.operation); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+
+			entries := s.History.GetAll()
+			if len(entries) != 1 || entries[0].Result != tt.stored {
+				t.Fatalf("%s: expected stored result %v, got %+v", tt.name, tt.stored, entries)
+			}
+			if got := s.formatCalculation(calculator.Result{Operation: tt.operation, Value: s.lastResult}); got != tt.formatted {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.formatted, got)
+			}
+		})
+	}
+}
+
+// TestEvaluateExpressionWholeNumbers tests that whole-number results print
+// without decimals unless force_decimals is set.
+func TestEvaluateExpressionWholeNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		force    bool
+		expected string
+	}{
+		{"integer product", "4 * 5", false, "4 * 5 = 20\n"},
+		{"fractional quotient", "10 / 3", false, "10 / 3 = 3.33\n"},
+		{"forced decimals", "4 * 5", true, "4 * 5 = 20.00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.ForceDecimals = tt.force
+
+			var out bytes.Buffer
+			previous := util.SetDefaultIO(util.NewIO(strings.NewReader(""), &out))
+			t.Cleanup(func() { util.SetDefaultIO(previous) })
+
+			if err := s.EvaluateExpression(tt.expr); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, out.String())
+			}
+		})
+	}
+}
+
+// TestPerformCalculationShowFraction tests appending fractions to results.
+func TestPerformCalculationShowFraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		show      bool
+		operation constants.Operation
+		input     string
+		expected  string
+	}{
+		{"three quarters", true, constants.OpDivision, "3\n4\n", "= 0.75 (3/4)"},
+		{"one third", true, constants.OpDivision, "1\n3\n", "= 0.33 (1/3)"},
+		{"whole numbers have none", true, constants.OpMultiplication, "4\n5\n", "= 20\n"},
+		{"irrational has none", true, constants.OpSquareRoot, "2\n", "= 1.41\n"},
+		{"off by default", false, constants.OpDivision, "3\n4\n", "= 0.75\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.ShowFraction = tt.show
+			logs := captureLogs(t)
+
+			if err := s.performCalculation(tt.operation); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+			if !strings.Contains(logs.String(), tt.expected) {
+				t.Errorf("%s: expected result %q, got logs:\n%s", tt.name, tt.expected, logs.String())
+			}
+		})
+	}
+}
+
+// TestPerformCalculationLargeFactorial tests the confirmation asked before
+// factorials above factorial_confirm_above.
+func TestPerformCalculationLargeFactorial(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		input     string
+		prompted  bool
+		cancelled bool
+	}{
+		{"confirmed", 100, "150\ny\n", true, false},
+		{"declined", 100, "150\nn\n", true, true},
+		{"enter declines", 100, "150\n\n", true, true},
+		{"at threshold", 100, "100\n", false, false},
+		{"small input", 100, "5\n", false, false},
+		{"disabled", 0, "150\n", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.FactorialConfirmAbove = tt.threshold
+			logs := captureLogs(t)
+
+			var out bytes.Buffer
+			util.SetDefaultIO(util.NewIO(strings.NewReader(tt.input), &out))
+
+			// Declining is not an error, so the menu shows no "Error:" line
+			if err := s.performCalculation(constants.OpFactorial); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+			if prompted := strings.Contains(out.String(), "Calculate it anyway?"); prompted != tt.prompted {
+				t.Errorf("%s: expected prompted %v, got %v", tt.name, tt.prompted, prompted)
+			}
+			if cancelled := strings.Contains(logs.String(), "cancelled by user"); cancelled != tt.cancelled {
+				t.Errorf("%s: expected cancelled %v, got logs:\n%s", tt.name, tt.cancelled, logs.String())
+			}
+			if tt.cancelled {
+				if s.History.Count() != 0 || s.hasLastResult {
+					t.Errorf("%s: expected nothing recorded, got %d entries (last result %v)", tt.name, s.History.Count(), s.hasLastResult)
+				}
+				return
+			}
+			if s.History.Count() != 1 {
+				t.Errorf("%s: expected 1 history entry, got %d", tt.name, s.History.Count())
+			}
+		})
+	}
+}
+
+// TestReadIntegerLimits tests that integer prompts accept any whole-number
+// notation and honor the operand limits like other number prompts.
+func TestReadIntegerLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		min, max float64
+		expected float64
+		hasError bool
+	}{
+		{"scientific notation", "1e1\n", -100, 100, 10, false},
+		{"above max re-prompts", "60\n5\n", -100, 50, 5, false},
+		{"below min re-prompts", "-3\n4\n", 0, 100, 4, false},
+		{"out of range every time", "60\n70\n80\n", -100, 50, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.MinOperand, s.Config.MaxOperand = tt.min, tt.max
+
+			num, err := s.readInteger("Enter number: ")
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %v", tt.name, num)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if num != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, num)
+			}
+		})
+	}
+}
+
+// TestPerformCalculationAverage tests reading a list of numbers for average.
+func TestPerformCalculationAverage(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		separator  string
+		expected   float64
+		expression string
+	}{
+		{"spaces", "2 4 6\n", ".", 4, "avg(2, 4, 6)"},
+		{"commas", "1, 2,3\n", ".", 2, "avg(1, 2, 3)"},
+		{"bad number re-prompts", "2 x\n10 20\n", ".", 15, "avg(10, 20)"},
+		{"empty line re-prompts", "\n5\n", ".", 5, "avg(5)"},
+		{"comma decimal separator", "1,5 2,5\n", ",", 2, "avg(1.5, 2.5)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.DecimalSeparator = tt.separator
+
+			if err := s.performCalculation(constants.OpAverage); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+
+			entries := s.History.GetAll()
+			if len(entries) != 1 || entries[0].Result != tt.expected || entries[0].Expression != tt.expression {
+				t.Errorf("%s: expected %s = %v, got %+v", tt.name, tt.expression, tt.expected, entries)
+			}
+		})
+	}
+}
+
+// TestSpokenResult tests that only exact integer results are spelled out.
+func TestSpokenResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		spoken   bool
+		result   calculator.Result
+		expected string
+	}{
+		{"integer", true, calculator.Result{Value: 1234, Exact: true}, "one thousand two hundred thirty-four"},
+		{"negative integer", true, calculator.Result{Value: -8, Exact: true}, "negative eight"},
+		{"fraction", true, calculator.Result{Value: 2.5, Exact: false}, ""},
+		{"disabled", false, calculator.Result{Value: 1234, Exact: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.SpokenOutput = tt.spoken
+
+			if got := s.spokenResult(tt.result); got != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestHandleSettingsToggles tests changing settings through the settings menu.
+func TestHandleSettingsToggles(t *testing.T) {
+	// Toggle clear screen, reject a bad choice, set precision, then go back
+	s := newTestService(t, "4\n9\n1\n5\n0\n")
+	s.Config.ClearScreen = true
+
+	if err := s.handleSettings(); err != nil {
+		t.Fatalf("handleSettings failed: %v", err)
+	}
+
+	if s.Config.ClearScreen {
+		t.Error("Expected ClearScreen to be toggled off")
+	}
+	if s.Config.Precision != 5 {
+		t.Errorf("Expected precision 5, got %d", s.Config.Precision)
+	}
+
+	// AutoSave is on by default, so the change is persisted
+	loaded, err := config.LoadFrom(*s.Config.ConfigPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if loaded.ClearScreen || loaded.Precision != 5 {
+		t.Errorf("Expected saved settings, got clear_screen=%v precision=%d", loaded.ClearScreen, loaded.Precision)
+	}
+}
+
+// TestHandleSettingsUndo tests undoing the last settings change.
+func TestHandleSettingsUndo(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		precision int
+		clear     bool
+	}{
+		{"undo precision", "1\n7\n6\n0\n", constants.DefaultPrecision, false},
+		{"only one level", "1\n7\n4\n6\n6\n0\n", 7, false},
+		{"failed change is not undone", "4\n1\nabc\n6\n0\n", constants.DefaultPrecision, false},
+		{"nothing to undo", "6\n0\n", constants.DefaultPrecision, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+
+			if err := s.handleSettings(); err != nil {
+				t.Fatalf("%s: handleSettings failed: %v", tt.name, err)
+			}
+
+			if s.Config.Precision != tt.precision {
+				t.Errorf("%s: expected precision %d, got %d", tt.name, tt.precision, s.Config.Precision)
+			}
+			if s.Config.ClearScreen != tt.clear {
+				t.Errorf("%s: expected ClearScreen %v, got %v", tt.name, tt.clear, s.Config.ClearScreen)
+			}
+
+			// The restored settings are saved, not just applied
+			loaded, err := config.LoadFrom(*s.Config.ConfigPath)
+			if err == nil && loaded.Precision != s.Config.Precision {
+				t.Errorf("%s: expected saved precision %d, got %d", tt.name, s.Config.Precision, loaded.Precision)
+			}
+		})
+	}
+}
+
+// TestPerformCalculationStoresRoundedResult tests that history keeps the displayed value.
+func TestPerformCalculationStoresRoundedResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		input     string
+		precision int
+		expected  float64
+	}{
+		{"float noise", constants.OpAddition, "0.1\n0.2\n", 2, 0.3},
+		{"repeating decimal", constants.OpDivision, "10\n3\n", 3, 3.333},
+		{"zero precision", constants.OpDivision, "7\n2\n", 0, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.Precision = tt.precision
+
+			if err := s.performCalculation(tt.operation); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+
+			entries := s.History.GetAll()
+			if len(entries) != 1 {
+				t.Fatalf("%s: expected 1 entry, got %d", tt.name, len(entries))
+			}
+			if entries[0].Result != tt.expected {
+				t.Errorf("%s: expected stored result %v, got %v", tt.name, tt.expected, entries[0].Result)
+			}
+		})
+	}
+}
+
+// TestEditConfigReloads tests that changes made in the editor are loaded back.
+func TestEditConfigReloads(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor is a shell script")
+	}
+	s := newTestService(t, "")
+
+	// A fake editor that rewrites the precision in the file it is given
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	body := "#!/bin/sh\nsed -i 's/\"precision\": [0-9]*/\"precision\": 7/' \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	if err := s.editConfig(); err != nil {
+		t.Fatalf("editConfig failed: %v", err)
+	}
+	if s.Config.Precision != 7 {
+		t.Errorf("Expected reloaded precision 7, got %d", s.Config.Precision)
+	}
+}
+
+// TestEditConfigNoEditor tests that a missing editor is reported without failing.
+func TestEditConfigNoEditor(t *testing.T) {
+	s := newTestService(t, "")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	if err := s.editConfig(); err != nil {
+		t.Errorf("Expected no error without an editor, got %v", err)
+	}
+}
+
+// TestReadNumberRespectsMinOperand tests that a floor of 0 rejects -5 but accepts 5.
+func TestReadNumberRespectsMinOperand(t *testing.T) {
+	s := newTestService(t, "-5\n5\n")
+	s.Config.MinOperand = 0
+	s.Config.MaxInputRetries = 2
+
+	result, err := s.readNumber("Enter number: ")
+	if err != nil {
+		t.Fatalf("readNumber failed: %v", err)
+	}
+	if result != 5 {
+		t.Errorf("Expected -5 to be rejected and 5 accepted, got %v", result)
+	}
+}
+
+// TestToggleHistory tests that nothing is recorded while history is disabled.
+func TestToggleHistory(t *testing.T) {
+	// Disable and clear, calculate twice, re-enable, calculate once
+	s := newTestService(t, "y\n1\n1\n2\n2\n3\n3\n")
+	s.History.AddSuccess("Addition", "0 + 0", 0)
+
+	if err := s.toggleHistory(); err != nil {
+		t.Fatalf("toggleHistory failed: %v", err)
+	}
+	if s.Config.SaveHistory {
+		t.Fatal("Expected history to be disabled")
+	}
+	if s.History.Count() != 0 {
+		t.Errorf("Expected existing entries to be cleared, got %d", s.History.Count())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.performCalculation(constants.OpAddition); err != nil {
+			t.Fatalf("performCalculation failed: %v", err)
+		}
+	}
+	if s.History.Count() != 0 {
+		t.Errorf("Expected no entries while disabled, got %d", s.History.Count())
+	}
+
+	if err := s.toggleHistory(); err != nil {
+		t.Fatalf("toggleHistory failed: %v", err)
+	}
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+	if s.History.Count() != 1 {
+		t.Errorf("Expected recording to resume with 1 entry, got %d", s.History.Count())
+	}
+}
+
+// TestToggleHistoryKeepsEntries tests disabling history without clearing it.
+func TestToggleHistoryKeepsEntries(t *testing.T) {
+	s := newTestService(t, "n\n")
+	s.History.AddSuccess("Addition", "0 + 0", 0)
+
+	if err := s.toggleHistory(); err != nil {
+		t.Fatalf("toggleHistory failed: %v", err)
+	}
+	if s.History.Count() != 1 {
+		t.Errorf("Expected existing entries to be kept, got %d", s.History.Count())
+	}
+}
+
+// TestWriteStatistics tests the -stats output in text and JSON against a seeded history.
+func TestWriteStatistics(t *testing.T) {
+	s := newTestService(t, "")
+	s.History.AddSuccess("Addition", "1 + 1", 2)
+	s.History.AddSuccess("Addition", "2 + 2", 4)
+	s.History.AddError("Division", "1 / 0", nil)
+
+	var text bytes.Buffer
+	if err := s.WriteStatistics(&text); err != nil {
+		t.Fatalf("WriteStatistics failed: %v", err)
+	}
+	for _, want := range []string{"Total calculations  : 3", "Successful          : 2", "Failed              : 1", "Most used operation : Addition"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected %q in text output:\n%s", want, text.String())
+		}
+	}
+
+	s.OutputFormat = constants.OutputFormatJSON
+	var out bytes.Buffer
+	if err := s.WriteStatistics(&out); err != nil {
+		t.Fatalf("WriteStatistics failed: %v", err)
+	}
+	var stats history.Statistics
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out.String(), err)
+	}
+	if stats.TotalCalculations != 3 || stats.SuccessfulCount != 2 || stats.FailedCount != 1 {
+		t.Errorf("Expected 3/2/1, got %d/%d/%d", stats.TotalCalculations, stats.SuccessfulCount, stats.FailedCount)
+	}
+}
+
+// TestAutoSaveInterval tests that an interval defers saving to the background
+// saver, which writes history when stopped.
+func TestAutoSaveInterval(t *testing.T) {
+	s := newTestService(t, "5\n3\n")
+	s.Config.AutoSaveInterval = 3600
+
+	stop := s.startAutoSave()
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation returned error: %v", err)
+	}
+
+	if _, err := os.Stat(s.History.FilePath); !os.IsNotExist(err) {
+		t.Fatalf("Expected no per-calculation save with an interval, got stat error %v", err)
+	}
+
+	stop()
+
+	saved := history.NewHistory(s.History.FilePath, 10)
+	if err := saved.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if saved.Count() != 1 {
+		t.Errorf("Expected the final save to write 1 entry, got %d", saved.Count())
+	}
+}
+
+// TestApplySettingsRestartsAutoSave tests that a new auto-save interval from
+// the settings menu replaces the running ticker instead of waiting for a restart.
+func TestApplySettingsRestartsAutoSave(t *testing.T) {
+	s := newTestService(t, "")
+	logs := captureLogs(t)
+	s.Config.AutoSaveInterval = 3600
+
+	s.stopAutoSave = s.startAutoSave()
+	s.History.AddSuccess("Addition", "2 + 2", 4)
+
+	s.Config.AutoSaveInterval = 60
+	s.applySettings()
+	defer s.stopAutoSave()
+
+	if !strings.Contains(logs.String(), "Saving history every 60s") {
+		t.Errorf("Expected the ticker restarted at 60s, got logs:\n%s", logs.String())
+	}
+
+	// Stopping the old ticker saved what it had
+	saved := history.NewHistory(s.History.FilePath, 10)
+	if err := saved.Load(); err != nil || saved.Count() != 1 {
+		t.Errorf("Expected the old ticker's final save to write 1 entry, got %d (%v)", saved.Count(), err)
+	}
+}
+
+// TestReadOnlyHistorySkipsRecording tests that calculations in read-only mode
+// are shown but neither recorded nor saved.
+func TestReadOnlyHistorySkipsRecording(t *testing.T) {
+	s := newTestService(t, "5\n3\n")
+	s.History.ReadOnly = true
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation returned error: %v", err)
+	}
+
+	if s.History.Count() != 0 {
+		t.Errorf("Expected no recorded entries, got %d", s.History.Count())
+	}
+	if _, err := os.Stat(s.History.FilePath); !os.IsNotExist(err) {
+		t.Errorf("Expected no history file, got stat error %v", err)
+	}
+}
+
+// TestPromptForLabel tests attaching an optional label after a calculation.
+func TestPromptForLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   bool
+		input    string
+		expected string
+	}{
+		{"label entered", true, "1200\n0.25\ntax estimate\n", "tax estimate"},
+		{"label skipped", true, "1200\n0.25\n\n", ""},
+		{"prompt disabled", false, "1200\n0.25\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.PromptForLabel = tt.prompt
+
+			if err := s.performCalculation(constants.OpMultiplication); err != nil {
+				t.Fatalf("%s: performCalculation returned error: %v", tt.name, err)
+			}
+
+			entries := s.History.GetAll()
+			if len(entries) != 1 {
+				t.Fatalf("%s: expected 1 entry, got %d", tt.name, len(entries))
+			}
+			if entries[0].Label != tt.expected {
+				t.Errorf("%s: expected label %q, got %q", tt.name, tt.expected, entries[0].Label)
+			}
+		})
+	}
+}
+
+// TestHandleExitConfirmDefault tests that Enter at the exit prompt follows ExitByDefault.
+func TestHandleExitConfirmDefault(t *testing.T) {
+	tests := []struct {
+		name          string
+		exitByDefault bool
+		expected      bool
+	}{
+		{"enter exits when default is yes", true, true},
+		{"enter stays when default is no", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "\n")
+			s.Config.ConfirmExit = true
+			s.Config.ExitByDefault = tt.exitByDefault
+			s.History.AddSuccess("Addition", "2 + 2", 4) // Unsaved, so the prompt is shown
+
+			shouldExit, err := s.handleExit()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if shouldExit != tt.expected {
+				t.Errorf("%s: expected exit %v, got %v", tt.name, tt.expected, shouldExit)
+			}
+		})
+	}
+}
+
+// TestHandleExitConfirmUnsaved tests that the exit prompt is only shown for
+// unsaved history unless confirm_exit_unsaved_only is turned off.
+func TestHandleExitConfirmUnsaved(t *testing.T) {
+	tests := []struct {
+		name        string
+		unsavedOnly bool
+		dirty       bool
+		prompted    bool
+	}{
+		{"clean history skips the prompt", true, false, false},
+		{"unsaved history asks", true, true, true},
+		{"always asks when turned off", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.ConfirmExit = true
+			s.Config.ConfirmExitUnsavedOnly = tt.unsavedOnly
+			s.Config.AutoSave = false
+			s.History.AddSuccess("Addition", "2 + 2", 4)
+			if !tt.dirty {
+				if err := s.History.Save(); err != nil {
+					t.Fatalf("%s: Save failed: %v", tt.name, err)
+				}
+			}
+
+			// Answer no, so a shown prompt keeps the calculator running
+			var out bytes.Buffer
+			util.SetDefaultIO(util.NewIO(strings.NewReader("n\n"), &out))
+
+			shouldExit, err := s.handleExit()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if prompted := strings.Contains(out.String(), "Are you sure you want to exit?"); prompted != tt.prompted {
+				t.Errorf("%s: expected prompted %v, got %v", tt.name, tt.prompted, prompted)
+			}
+			if shouldExit == tt.prompted {
+				t.Errorf("%s: expected exit %v, got %v", tt.name, !tt.prompted, shouldExit)
+			}
+		})
+	}
+}
+
+// TestRunSetupWizard tests that first-run answers are applied and saved.
+func TestRunSetupWizard(t *testing.T) {
+	s := newTestService(t, "5\nn\nn\n")
+	s.firstRun = true
+	configPath := *s.Config.ConfigPath
+
+	if err := s.runSetupWizard(); err != nil {
+		t.Fatalf("runSetupWizard returned error: %v", err)
+	}
+
+	if s.firstRun {
+		t.Error("Expected firstRun to be cleared")
+	}
+	if s.Config.Precision != 5 || s.Config.SaveHistory {
+		t.Errorf("Expected precision 5 without history, got %d and %v", s.Config.Precision, s.Config.SaveHistory)
+	}
+
+	saved, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if saved.Precision != 5 || saved.SaveHistory {
+		t.Errorf("Expected saved precision 5 without history, got %d and %v", saved.Precision, saved.SaveHistory)
+	}
+}
+
+// TestRunSetupWizardKeepsFlags tests that settings made before the wizard,
+// as command-line flags do, survive it and are saved with its answers.
+func TestRunSetupWizardKeepsFlags(t *testing.T) {
+	s := newTestService(t, "\ny\n\n")
+	s.firstRun = true
+	s.Config.ColorOutput = false // As -no-color sets it
+	if err := s.SetPrecision(6); err != nil {
+		t.Fatalf("SetPrecision failed: %v", err)
+	}
+	if err := s.SetMaxHistory(3); err != nil {
+		t.Fatalf("SetMaxHistory failed: %v", err)
+	}
+
+	if err := s.runSetupWizard(); err != nil {
+		t.Fatalf("runSetupWizard returned error: %v", err)
+	}
+
+	if s.Config.Precision != 6 || s.Config.MaxHistory != 3 || !s.Config.ColorOutput {
+		t.Errorf("Expected precision 6, max history 3 and color on, got %d, %d and %v",
+			s.Config.Precision, s.Config.MaxHistory, s.Config.ColorOutput)
+	}
+	if s.History.MaxSize != 3 {
+		t.Errorf("Expected history size 3 to match the config, got %d", s.History.MaxSize)
+	}
+
+	saved, err := config.LoadFrom(*s.Config.ConfigPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if saved.Precision != 6 || saved.MaxHistory != 3 {
+		t.Errorf("Expected saved precision 6 and max history 3, got %d and %d", saved.Precision, saved.MaxHistory)
+	}
+}
+
+// TestSetMaxHistory tests that lowering the size trims loaded entries.
+func TestSetMaxHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		expected int
+		hasError bool
+	}{
+		{"lowered trims oldest", 2, 2, false},
+		{"raised keeps all", 50, 5, false},
+		{"zero empties", 0, 0, false},
+		{"above range", 10001, 5, true},
+		{"negative", -3, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			for i := 1; i <= 5; i++ {
+				s.History.AddSuccess("Addition", fmt.Sprintf("%d + 0", i), float64(i))
+			}
+			previous := s.Config.MaxHistory
+
+			err := s.SetMaxHistory(tt.max)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				if s.Config.MaxHistory != previous {
+					t.Errorf("%s: expected MaxHistory to stay %d, got %d", tt.name, previous, s.Config.MaxHistory)
+				}
+			} else if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			if s.History.Count() != tt.expected {
+				t.Fatalf("%s: expected %d entries, got %d", tt.name, tt.expected, s.History.Count())
+			}
+			if tt.expected > 0 {
+				if last := s.History.GetRecent(1)[0]; last.Result != 5 {
+					t.Errorf("%s: expected the newest entry to be kept, got %v", tt.name, last.Result)
+				}
+			}
+		})
+	}
+}
+
+// TestEvaluateExpressionPrecision tests that one-shot mode formats results
+// with the -precision override rather than a fixed precision.
+func TestEvaluateExpressionPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision int
+		format    string
+		expected  string
+		hasError  bool
+	}{
+		{"config default", constants.DefaultPrecision, constants.OutputFormatText, "10 / 3 = 3.33\n", false},
+		{"four places", 4, constants.OutputFormatText, "10 / 3 = 3.3333\n", false},
+		{"four places as JSON", 4, constants.OutputFormatJSON, `"formatted":"3.3333"`, false},
+		{"zero places", 0, constants.OutputFormatText, "10 / 3 = 3\n", false},
+		{"above range", 16, constants.OutputFormatText, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.OutputFormat = tt.format
+
+			var out bytes.Buffer
+			previous := util.SetDefaultIO(util.NewIO(strings.NewReader(""), &out))
+			t.Cleanup(func() { util.SetDefaultIO(previous) })
+
+			err := s.SetPrecision(tt.precision)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				if s.Config.Precision != constants.DefaultPrecision {
+					t.Errorf("%s: expected precision to stay %d, got %d", tt.name, constants.DefaultPrecision, s.Config.Precision)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			if err := s.EvaluateExpression("10 / 3"); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if !strings.Contains(out.String(), tt.expected) {
+				t.Errorf("%s: expected output to contain %q, got %q", tt.name, tt.expected, out.String())
+			}
+		})
+	}
+}
+
+// TestCopyResult tests that results are copied only when CopyResult is set
+// and that a missing clipboard tool doesn't fail the calculation.
+func TestCopyResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		copy     bool
+		copyErr  error
+		expected []string
+	}{
+		{"copies when enabled", true, nil, []string{"84"}},
+		{"off by default", false, nil, nil},
+		{"no clipboard tool", true, errors.ErrNoClipboard, []string{"84"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "12\n7\n")
+			s.CopyResult = tt.copy
+
+			var copied []string
+			previous := copyToClipboard
+			copyToClipboard = func(text string) error {
+				copied = append(copied, text)
+				return tt.copyErr
+			}
+			t.Cleanup(func() { copyToClipboard = previous })
+
+			if err := s.performCalculation(constants.OpMultiplication); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+			if err := s.EvaluateExpression("12 * 7"); err != nil {
+				t.Fatalf("%s: EvaluateExpression failed: %v", tt.name, err)
+			}
+
+			// One copy from the menu and one from the expression
+			var expected []string
+			for range 2 {
+				expected = append(expected, tt.expected...)
+			}
+			if strings.Join(copied, ",") != strings.Join(expected, ",") {
+				t.Errorf("%s: expected copies %q, got %q", tt.name, expected, copied)
+			}
+		})
+	}
+}
+
+// TestHandleQuickOperations tests running a pinned operation from the Quick menu.
+func TestHandleQuickOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		pinned   []string
+		input    string
+		expected string // Expression recorded in history; empty means none
+	}{
+		{"second pin", []string{"add", "^"}, "2\n2\n10\nn\n\n", "2.00 ^ 10.00"},
+		{"out of range then back", []string{"add"}, "5\n0\n", ""},
+		{"nothing pinned", nil, "\n", ""},
+		{"unknown pin skipped", []string{"logarithm", "/"}, "1\n9\n3\nn\n\n", "9.00 / 3.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.PinnedOperations = tt.pinned
+
+			if err := s.handleQuickOperations(); err != nil {
+				t.Fatalf("%s: handleQuickOperations failed: %v", tt.name, err)
+			}
+
+			entries := s.History.GetAll()
+			if tt.expected == "" {
+				if len(entries) != 0 {
+					t.Errorf("%s: expected no calculation, got %+v", tt.name, entries)
+				}
+				return
+			}
+			if len(entries) != 1 || entries[0].Expression != tt.expected {
+				t.Errorf("%s: expected %q recorded, got %+v", tt.name, tt.expected, entries)
+			}
+		})
+	}
+}
+
+// TestAuditLogSurvivesHistoryClear tests that every calculation appends one
+// audit line and that clearing history leaves the audit log alone.
+func TestAuditLogSurvivesHistoryClear(t *testing.T) {
+	s := newTestService(t, "2\n3\n")
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := logger.OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("OpenAuditLog failed: %v", err)
+	}
+	t.Cleanup(func() { audit.Close() })
+	s.audit = audit
+
+	auditLines := func() []string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read audit log: %v", err)
+		}
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+	if err := s.EvaluateExpression("10 / 0"); err == nil {
+		t.Fatal("Expected division by zero error")
+	}
+	if lines := auditLines(); len(lines) != 2 {
+		t.Fatalf("Expected 2 audit lines, got %d: %q", len(lines), lines)
+	}
+
+	s.History.Clear()
+	if err := s.History.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Auditing doesn't depend on history being saved either
+	s.Config.SaveHistory = false
+	if err := s.EvaluateExpression("4 * 5"); err != nil {
+		t.Fatalf("EvaluateExpression failed: %v", err)
+	}
+
+	lines := auditLines()
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 audit lines after clearing history, got %d: %q", len(lines), lines)
+	}
+	for i, want := range []string{`"expression":"2.00 + 3.00"`, `"error":"`, `"expression":"4 * 5"`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("Expected audit line %d to contain %s, got %s", i+1, want, lines[i])
+		}
+	}
+}
+
+// TestRunRecoversFromPanic tests that a panicking menu handler is logged,
+// history is saved, and the menu keeps running until the user exits.
+func TestRunRecoversFromPanic(t *testing.T) {
+	// Pick Basic (which panics), press Enter at the error, then Exit
+	s := newTestService(t, "1\n\n7\n")
+	logs := captureLogs(t)
+	s.History.AddSuccess("Addition", "2 + 2", 4)
+
+	previous := menuHandler
+	menuHandler = func(s *Service, option constants.MenuOption) (bool, error) {
+		if option == constants.MenuBasicCalculator {
+			panic("handler exploded")
+		}
+		return previous(s, option)
+	}
+	t.Cleanup(func() { menuHandler = previous })
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("Expected Run to exit cleanly, got %v", err)
+	}
+
+	if !strings.Contains(logs.String(), "[ERROR] Recovered from panic in menu option 1: handler exploded") {
+		t.Errorf("Expected the panic to be logged, got logs:\n%s", logs.String())
+	}
+
+	saved := history.NewHistory(*s.Config.HistoryPath, 10)
+	if err := saved.Load(); err != nil || saved.Count() != 1 {
+		t.Errorf("Expected history saved after the panic, got %d entries (%v)", saved.Count(), err)
+	}
+}
+
+// brokenPipeWriter fails every write like stdout piped into a finished `head`.
+type brokenPipeWriter struct{}
+
+func (brokenPipeWriter) Write([]byte) (int, error) {
+	return 0, syscall.EPIPE
+}
+
+// TestRunStopsWhenOutputClosed tests that the menu exits cleanly, saving
+// history, once nobody reads its output.
+func TestRunStopsWhenOutputClosed(t *testing.T) {
+	s := newTestService(t, "")
+	logs := captureLogs(t)
+	s.History.AddSuccess("Addition", "2 + 2", 4)
+
+	// Plenty of input remains, so only the closed output can end the loop
+	util.SetDefaultIO(util.NewIO(strings.NewReader(strings.Repeat("1\n5\n3\n\n", 10)), brokenPipeWriter{}))
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("Expected Run to stop cleanly, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "Output closed") {
+		t.Errorf("Expected the closed output to be logged, got logs:\n%s", logs.String())
+	}
+
+	saved := history.NewHistory(*s.Config.HistoryPath, 10)
+	if err := saved.Load(); err != nil || saved.Count() != 1 {
+		t.Errorf("Expected history saved on exit, got %d entries (%v)", saved.Count(), err)
+	}
+}
+
+// TestHandleMenuOptionSafely tests that a recovered panic becomes an ErrInternal error.
+func TestHandleMenuOptionSafely(t *testing.T) {
+	s := newTestService(t, "")
+	captureLogs(t)
+
+	previous := menuHandler
+	menuHandler = func(*Service, constants.MenuOption) (bool, error) {
+		var m map[string]int
+		m["boom"] = 1 // Runtime panic: assignment to entry in nil map
+		return true, nil
+	}
+	t.Cleanup(func() { menuHandler = previous })
+
+	shouldExit, err := s.handleMenuOptionSafely(constants.MenuHelp)
+	if shouldExit {
+		t.Error("Expected the menu to keep running after a panic")
+	}
+	if !stderrors.Is(err, errors.ErrInternal) || !strings.Contains(err.Error(), "nil map") {
+		t.Errorf("Expected an ErrInternal error describing the panic, got %v", err)
+	}
+}
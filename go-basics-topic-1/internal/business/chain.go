@@ -0,0 +1,93 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/util"
+	"cli-calculator/internal/validation"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// continueCalculation lets the user keep applying operations to the last
+// result, like a pocket calculator: after "5 + 3 = 8", entering "* 2" gives 16.
+// Single-operand operations need no number ("sqrt", "!"). An empty line ends
+// the chain; invalid input is reported and the running value is kept.
+// It only prompts when chain_results is on, so scripted input isn't asked
+// for an extra line after every calculation.
+func (s *Service) continueCalculation() error {
+	if !s.Config.ChainResults {
+		return nil
+	}
+
+	for s.hasLastResult {
+		prompt := fmt.Sprintf("Continue from %s (e.g. '* 2'), or press Enter to finish: ", s.formatResult(s.lastResult))
+		input, err := util.GetUserInput(prompt)
+		if err != nil {
+			return err
+		}
+		if input == "" {
+			return nil
+		}
+
+		operation, operands, err := s.parseChainInput(input)
+		if err != nil {
+			util.PrintError(err)
+			continue
+		}
+
+		if err := s.calculateAndRecord(operation, operands); err != nil {
+			util.PrintError(err)
+		}
+	}
+	return nil
+}
+
+// parseChainInput splits input such as "* 2", "*2", "times 2", or "!" into an
+// operation and its operands, with the running value as the first operand.
+func (s *Service) parseChainInput(input string) (constants.Operation, []float64, error) {
+	name, rest := splitOperator(input)
+
+	operation, err := calculator.ResolveOperation(name)
+	if err != nil {
+		return constants.OpUnknown, nil, err
+	}
+
+	// Square root and factorial apply to the running value alone
+	if operation == constants.OpSquareRoot || operation == constants.OpFactorial {
+		if rest != "" {
+			return constants.OpUnknown, nil, errors.NewValidationError("operand", rest, fmt.Sprintf("%s takes no second number", operation.String()))
+		}
+		return operation, []float64{s.lastResult}, nil
+	}
+
+	if rest == "" {
+		return constants.OpUnknown, nil, errors.NewValidationError("operand", input, "enter an operator followed by a number")
+	}
+
+	opts := s.calcOptions()
+	num, err := validation.ValidateNumberInRange(rest, s.Config.DecimalSeparator, opts.MinOperand, opts.MaxOperand)
+	if err != nil {
+		return constants.OpUnknown, nil, err
+	}
+
+	return operation, []float64{s.lastResult, num}, nil
+}
+
+// splitOperator separates the leading operator from the rest of the input.
+// A word operator ends at a space; a symbol ends where the number begins.
+func splitOperator(input string) (string, string) {
+	input = strings.TrimSpace(input)
+	if fields := strings.Fields(input); len(fields) > 1 {
+		return fields[0], strings.TrimSpace(strings.TrimPrefix(input, fields[0]))
+	}
+
+	runes := []rune(input)
+	if len(runes) == 0 || unicode.IsLetter(runes[0]) {
+		return input, ""
+	}
+	// Symbols are a single character (e.g. "*2", "-3", "√")
+	return string(runes[0]), strings.TrimSpace(string(runes[1:]))
+}
@@ -0,0 +1,103 @@
+package businessService
+
+import (
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/util"
+	"testing"
+)
+
+// TestContinueCalculation tests chaining operations onto the previous result.
+func TestContinueCalculation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+	}{
+		{"multiply with space", "5\n3\n* 2\n\n", 16},
+		{"multiply without space", "5\n3\n*2\n\n", 16},
+		{"word operator", "5\n3\ntimes 2\n\n", 16},
+		{"several steps", "5\n3\n* 2\n- 6\n/ 5\n\n", 2},
+		{"single-operand operation", "5\n3\n- 4\n!\n\n", 24},
+		{"bad input keeps value", "5\n3\n* abc\n+ 1\n\n", 9},
+		{"finish immediately", "5\n3\n\n", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.ChainResults = true
+
+			if err := s.performCalculation(constants.OpAddition); err != nil {
+				t.Fatalf("%s: performCalculation failed: %v", tt.name, err)
+			}
+			if err := s.continueCalculation(); err != nil {
+				t.Fatalf("%s: continueCalculation failed: %v", tt.name, err)
+			}
+
+			if s.lastResult != tt.expected {
+				t.Errorf("%s: expected running value %v, got %v", tt.name, tt.expected, s.lastResult)
+			}
+		})
+	}
+}
+
+// TestContinueCalculationRecordsHistory tests that chained steps are recorded.
+func TestContinueCalculationRecordsHistory(t *testing.T) {
+	s := newTestService(t, "5\n3\n* 2\n\n")
+	s.Config.ChainResults = true
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+	if err := s.continueCalculation(); err != nil {
+		t.Fatalf("continueCalculation failed: %v", err)
+	}
+
+	entries := s.History.GetAll()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].Expression != "8.00 * 2.00" || entries[1].Result != 16 {
+		t.Errorf("Expected '8.00 * 2.00' = 16, got %q = %v", entries[1].Expression, entries[1].Result)
+	}
+}
+
+// TestContinueCalculationMinOperand tests that a floor of 0 applies to the
+// typed numbers but not to a negative running value.
+func TestContinueCalculationMinOperand(t *testing.T) {
+	s := newTestService(t, "3\n5\n+ 4\n- -1\n\n")
+	s.Config.MinOperand = 0
+	s.Config.ChainResults = true
+
+	if err := s.performCalculation(constants.OpSubtraction); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+	if err := s.continueCalculation(); err != nil {
+		t.Fatalf("continueCalculation failed: %v", err)
+	}
+
+	// -2 + 4 is accepted; "- -1" is rejected and leaves the value at 2
+	if s.lastResult != 2 {
+		t.Errorf("Expected running value 2, got %v", s.lastResult)
+	}
+}
+
+// TestContinueCalculationOffByDefault tests that without chain_results no
+// chain prompt is shown, leaving the next input line for the menu.
+func TestContinueCalculationOffByDefault(t *testing.T) {
+	s := newTestService(t, "5\n3\n* 2\n")
+
+	if err := s.performCalculation(constants.OpAddition); err != nil {
+		t.Fatalf("performCalculation failed: %v", err)
+	}
+	if err := s.continueCalculation(); err != nil {
+		t.Fatalf("continueCalculation failed: %v", err)
+	}
+
+	if s.lastResult != 8 {
+		t.Errorf("Expected running value 8, got %v", s.lastResult)
+	}
+	if next, err := util.GetUserInput(""); err != nil || next != "* 2" {
+		t.Errorf("Expected the next line left unread, got %q (%v)", next, err)
+	}
+}
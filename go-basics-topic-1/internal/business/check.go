@@ -0,0 +1,69 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/config"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/history"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Kinds of file CheckFile recognizes.
+const (
+	CheckKindConfig  = "config"
+	CheckKindHistory = "history"
+)
+
+// CheckFile parses the config or history file at path without starting the
+// calculator and reports whether it is valid, for linting files in CI.
+// A JSON object with an "entries" key is treated as history; anything else
+// as config. It returns the detected kind even when the check fails.
+//
+// Config problems, including unknown keys that would otherwise be silently
+// ignored, wrap errors.ErrConfigInvalid. Unreadable or malformed files and
+// broken history entries are reported as FileErrors.
+func CheckFile(path string) (kind string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.NewFileError(path, "read", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", errors.NewFileError(path, "parse", err)
+	}
+
+	if _, ok := fields["entries"]; ok {
+		return CheckKindHistory, checkHistory(path, data)
+	}
+	return CheckKindConfig, checkConfig(data)
+}
+
+// checkHistory parses history data and checks its entries.
+func checkHistory(path string, data []byte) error {
+	var h history.History
+	if err := json.Unmarshal(data, &h); err != nil {
+		return errors.NewFileError(path, "parse", err)
+	}
+	if err := h.Validate(); err != nil {
+		return errors.NewFileError(path, "validate", err)
+	}
+	return nil
+}
+
+// checkConfig parses config data strictly and validates it.
+func checkConfig(data []byte) error {
+	cfg := config.DefaultConfig()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields() // Catch misspelled keys
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrConfigInvalid, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrConfigInvalid, err)
+	}
+	return nil
+}
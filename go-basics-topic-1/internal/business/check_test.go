@@ -0,0 +1,109 @@
+package businessService
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheckFile tests linting config and history files.
+func TestCheckFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		contents      string
+		kind          string
+		hasError      bool
+		configInvalid bool   // Error wraps ErrConfigInvalid
+		mentions      string // Text the error must contain
+	}{
+		{
+			name:     "valid config",
+			contents: `{"precision": 4, "save_history": true, "max_history": 50}`,
+			kind:     CheckKindConfig,
+		},
+		{
+			name:          "invalid precision",
+			contents:      `{"precision": 20}`,
+			kind:          CheckKindConfig,
+			hasError:      true,
+			configInvalid: true,
+			mentions:      "precision='20'",
+		},
+		{
+			name:          "misspelled config key",
+			contents:      `{"precison": 4}`,
+			kind:          CheckKindConfig,
+			hasError:      true,
+			configInvalid: true,
+			mentions:      "precison",
+		},
+		{
+			name:     "valid history",
+			contents: `{"entries": [{"timestamp": "2024-01-01T10:00:00Z", "operation": "Addition", "expression": "2 + 2", "result": 4, "success": true}], "max_size": 100}`,
+			kind:     CheckKindHistory,
+		},
+		{
+			name:     "malformed history",
+			contents: `{"entries": [{"timestamp": "yesterday"}]}`,
+			kind:     CheckKindHistory,
+			hasError: true,
+			mentions: "parse",
+		},
+		{
+			name:     "history entry without operation",
+			contents: `{"entries": [{"timestamp": "2024-01-01T10:00:00Z", "expression": "2 + 2", "result": 4, "success": true}]}`,
+			kind:     CheckKindHistory,
+			hasError: true,
+			mentions: "entries[0].operation",
+		},
+		{
+			name:     "not JSON",
+			contents: `precision = 4`,
+			hasError: true,
+			mentions: "parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file.json")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			kind, err := CheckFile(path)
+			if kind != tt.kind {
+				t.Errorf("%s: expected kind %q, got %q", tt.name, tt.kind, kind)
+			}
+			if !tt.hasError {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tt.name)
+			}
+			if got := stderrors.Is(err, errors.ErrConfigInvalid); got != tt.configInvalid {
+				t.Errorf("%s: expected ErrConfigInvalid %v, got %v (%v)", tt.name, tt.configInvalid, got, err)
+			}
+			if !strings.Contains(err.Error(), tt.mentions) {
+				t.Errorf("%s: expected error to mention %q, got %v", tt.name, tt.mentions, err)
+			}
+		})
+	}
+}
+
+// TestCheckFileMissing tests that a missing file is a FileError.
+func TestCheckFileMissing(t *testing.T) {
+	_, err := CheckFile(filepath.Join(t.TempDir(), "missing.json"))
+
+	var fileErr *errors.FileError
+	if !stderrors.As(err, &fileErr) || !fileErr.IsNotExist() {
+		t.Errorf("Expected a not-exist FileError, got %v", err)
+	}
+}
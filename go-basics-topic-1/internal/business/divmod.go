@@ -0,0 +1,56 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"fmt"
+)
+
+// divModOperation is the operation name recorded for quotient and remainder.
+const divModOperation = "Quotient and Remainder"
+
+// advancedDivMod is the advanced menu choice for quotient and remainder.
+// It shows two results, so it has its own flow instead of an Operation.
+const advancedDivMod = "10"
+
+// handleDivMod reads a dividend and divisor and shows the whole-number
+// quotient and the remainder together. History records the quotient, with
+// the expression written as divmod(a, b) so -replay skips it.
+func (s *Service) handleDivMod() error {
+	a, err := s.readNumber("Enter dividend: ")
+	if err != nil {
+		return err
+	}
+	b, err := s.readNumber("Enter divisor: ")
+	if err != nil {
+		return err
+	}
+
+	expression := fmt.Sprintf("divmod(%s, %s)", plainNumber(a), plainNumber(b))
+
+	quotient, remainder, err := calculator.DivMod(a, b)
+	s.recordAudit(divModOperation, expression, quotient, err)
+	if err != nil {
+		if s.Config.SaveHistory {
+			s.History.AddError(divModOperation, expression, err)
+		}
+		return err
+	}
+	s.lastResult, s.hasLastResult = quotient, true
+
+	// The quotient is whole and math.Mod is exact, so both print without
+	// decimals when they are whole numbers, like other exact results
+	resultStr := fmt.Sprintf("%s remainder %s",
+		s.formatCalculation(calculator.Result{Value: quotient, Exact: true}),
+		s.formatCalculation(calculator.Result{Value: remainder, Exact: true}))
+	util.PrintResult(divModOperation, expression, resultStr)
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess(divModOperation, expression, calculator.RoundTo(quotient, s.Config.Precision))
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("Quotient and remainder calculated: %s = %s", expression, resultStr)
+	return nil
+}
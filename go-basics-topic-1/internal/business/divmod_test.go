@@ -0,0 +1,81 @@
+package businessService
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+// TestHandleDivMod tests the quotient and remainder flow and what it records.
+func TestHandleDivMod(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		expected   float64
+		expression string
+		hasError   bool
+	}{
+		{"17 / 5", "17\n5\n", 3, "divmod(17, 5)", false},
+		{"negative dividend", "-17\n5\n", -3, "divmod(-17, 5)", false},
+		{"divide by zero", "17\n0\n", 0, "divmod(17, 0)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+
+			err := s.handleDivMod()
+			entries := s.History.GetAll()
+			if len(entries) != 1 || entries[0].Expression != tt.expression {
+				t.Fatalf("%s: expected 1 entry for %s, got %+v", tt.name, tt.expression, entries)
+			}
+
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrDivisionByZero) {
+					t.Errorf("%s: expected ErrDivisionByZero, got %v", tt.name, err)
+				}
+				if entries[0].Success {
+					t.Errorf("%s: expected a failed entry", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if entries[0].Result != tt.expected {
+				t.Errorf("%s: expected quotient %v, got %v", tt.name, tt.expected, entries[0].Result)
+			}
+		})
+	}
+}
+
+// TestHandleDivModFormatting tests that whole quotients and remainders print
+// without decimals, like other exact results.
+func TestHandleDivModFormatting(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		forceDecimals bool
+		expected      string
+	}{
+		{"whole numbers", "17\n5\n", false, "= 3 remainder 2\n"},
+		{"fractional remainder", "7.5\n2\n", false, "= 3 remainder 1.50\n"},
+		{"force decimals", "17\n5\n", true, "= 3.00 remainder 2.00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			s.Config.ForceDecimals = tt.forceDecimals
+			logs := captureLogs(t)
+
+			if err := s.handleDivMod(); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if !strings.Contains(logs.String(), tt.expected) {
+				t.Errorf("%s: expected %q, got logs:\n%s", tt.name, tt.expected, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"fmt"
+	"strconv"
+)
+
+// compoundInterestOperation is the operation name recorded for compound interest.
+const compoundInterestOperation = "Compound Interest"
+
+// advancedCompoundInterest is the advanced menu choice for compound interest.
+// It collects four inputs, so it has its own flow instead of an Operation.
+const advancedCompoundInterest = "7"
+
+// handleCompoundInterest collects principal, rate, compounding periods, and
+// years, then shows and records the final amount. The expression is stored as
+// the infix formula, so -replay can verify it like any other calculation.
+func (s *Service) handleCompoundInterest() error {
+	principal, err := s.readNumber("Enter principal: ")
+	if err != nil {
+		return err
+	}
+	rate, err := s.readNumber("Enter annual interest rate (e.g. 0.05 for 5%): ")
+	if err != nil {
+		return err
+	}
+	periods, err := s.readNumber("Enter compounding periods per year (e.g. 12 for monthly): ")
+	if err != nil {
+		return err
+	}
+	years, err := s.readNumber("Enter number of years: ")
+	if err != nil {
+		return err
+	}
+
+	expression := fmt.Sprintf("%s * (1 + %s / %s) ^ (%s * %s)",
+		plainNumber(principal), plainNumber(rate), plainNumber(periods), plainNumber(periods), plainNumber(years))
+
+	amount, err := calculator.CompoundInterest(principal, rate, periods, years)
+	s.recordAudit(compoundInterestOperation, expression, amount, err)
+	if err != nil {
+		if s.Config.SaveHistory {
+			s.History.AddError(compoundInterestOperation, expression, err)
+		}
+		return err
+	}
+	s.lastResult, s.hasLastResult = amount, true
+
+	resultStr := s.formatResult(amount)
+	util.PrintResult(compoundInterestOperation, expression, resultStr)
+	util.PrintInfo(fmt.Sprintf("Interest earned: %s", s.formatResult(amount-principal)))
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess(compoundInterestOperation, expression, calculator.RoundTo(amount, s.Config.Precision))
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("Compound interest calculated: %s = %s", expression, resultStr)
+	return nil
+}
+
+// plainNumber renders v without an exponent so the infix evaluator can parse it.
+func plainNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,58 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"testing"
+)
+
+// TestHandleCompoundInterest tests the four-input flow and what it records.
+func TestHandleCompoundInterest(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+		hasError bool
+	}{
+		{"monthly for ten years", "1000\n0.05\n12\n10\n", 1647.01, false},
+		{"yearly for two years", "100\n0.1\n1\n2\n", 121, false},
+		{"negative rate", "1000\n-0.05\n12\n10\n", 0, true},
+		{"zero periods", "1000\n0.05\n0\n10\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+
+			err := s.handleCompoundInterest()
+			entries := s.History.GetAll()
+			if len(entries) != 1 {
+				t.Fatalf("%s: expected 1 history entry, got %d", tt.name, len(entries))
+			}
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				if entries[0].Success {
+					t.Errorf("%s: expected a failed entry", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if entries[0].Result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, entries[0].Result)
+			}
+
+			// The stored formula must evaluate back to the same amount
+			replayed, err := calculator.Evaluate(entries[0].Expression)
+			if err != nil {
+				t.Fatalf("%s: stored expression %q does not evaluate: %v", tt.name, entries[0].Expression, err)
+			}
+			if calculator.RoundTo(replayed, 2) != tt.expected {
+				t.Errorf("%s: expression %q evaluates to %v, expected %v", tt.name, entries[0].Expression, replayed, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/history"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// replayableOperations are the history operations whose expressions the
+// infix evaluator can parse: -expr and -batch entries, and the binary
+// arithmetic operations ("2.00 + 3.00"). Everything else, such as square
+// roots (√x), factorials (n!), averages (avg(5)), roundings, RPN lines, and
+// quotients with remainders, is stored in a form only its own menu understands.
+var replayableOperations = map[string]bool{
+	"Expression":                        true,
+	constants.OpAddition.String():       true,
+	constants.OpSubtraction.String():    true,
+	constants.OpMultiplication.String(): true,
+	constants.OpDivision.String():       true,
+	constants.OpPower.String():          true,
+	constants.OpModulo.String():         true,
+}
+
+// ReplayMismatch describes a history entry whose stored result no longer
+// matches a fresh evaluation of its expression.
+type ReplayMismatch struct {
+	Index      int     // Position of the entry in the history file
+	Expression string  // Expression as stored
+	Stored     float64 // Result recorded in the file
+	Recomputed float64 // Result of evaluating the expression now
+	Err        error   // Set when the expression no longer evaluates at all
+}
+
+// ReplayReport summarizes a replay of a history file.
+type ReplayReport struct {
+	Checked    int // Entries re-evaluated
+	Skipped    int // Failed entries and expressions the infix evaluator can't parse
+	Mismatches []ReplayMismatch
+}
+
+// Replay loads the history file at path and re-evaluates every successful
+// expression with the infix evaluator, collecting entries whose result differs.
+// Entries of operations not in replayableOperations are counted as skipped.
+// This demonstrates reusing one component (the evaluator) to verify another's output.
+func (s *Service) Replay(path string) (ReplayReport, error) {
+	var report ReplayReport
+
+	// A missing file is an error here, unlike at startup
+	if _, err := os.Stat(path); err != nil {
+		return report, errors.NewFileError(path, "read", err)
+	}
+
+	// Replay every entry regardless of the configured history limit
+	replayed := history.NewHistory(path, 0)
+	replayed.MaxSize = math.MaxInt
+	if err := replayed.Load(); err != nil {
+		return report, err
+	}
+
+	for i, entry := range replayed.GetAll() {
+		if !entry.Success || !replayableOperations[entry.Operation] {
+			report.Skipped++
+			continue
+		}
+
+		report.Checked++
+		result, _, err := calculator.EvaluateVerboseWithOptions(entry.Expression, s.calcOptions())
+		// Results are stored rounded to the display precision, so compare at that precision
+		precision := s.Config.Precision
+		if op, ok := constants.LookupOperation(entry.Operation); ok {
+			precision = s.Config.PrecisionFor(op)
+		}
+		if err != nil || !calculator.AlmostEqual(calculator.RoundTo(result, precision), calculator.RoundTo(entry.Result, precision), constants.DefaultEpsilon) {
+			report.Mismatches = append(report.Mismatches, ReplayMismatch{
+				Index:      i,
+				Expression: entry.Expression,
+				Stored:     entry.Result,
+				Recomputed: result,
+				Err:        err,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// WriteReplayReport writes a human-readable summary of a replay to w.
+func WriteReplayReport(w io.Writer, report ReplayReport) error {
+	if _, err := fmt.Fprintf(w, "Replayed %d entries (%d skipped), %d mismatches\n",
+		report.Checked, report.Skipped, len(report.Mismatches)); err != nil {
+		return err
+	}
+
+	for _, m := range report.Mismatches {
+		// Full precision, since the difference may be below the display precision
+		var err error
+		if m.Err != nil {
+			_, err = fmt.Fprintf(w, "  #%d %s: stored %v, now fails: %v\n", m.Index+1, m.Expression, m.Stored, m.Err)
+		} else {
+			_, err = fmt.Fprintf(w, "  #%d %s: stored %v, recomputed %v\n", m.Index+1, m.Expression, m.Stored, m.Recomputed)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/history"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReplayReportsTamperedResult tests that a modified stored result is reported.
+func TestReplayReportsTamperedResult(t *testing.T) {
+	s := newTestService(t, "")
+	path := filepath.Join(t.TempDir(), "replay.json")
+
+	h := history.NewHistory(path, 10)
+	h.AddSuccess("Addition", "2.00 + 3.00", 5)
+	h.AddSuccess("Expression", "2 + 3 * 4", 99) // Tampered: should be 14
+	h.AddSuccess("Square Root", "√9.00", 3)
+	h.AddError("Division", "1.00 / 0.00", nil)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	report, err := s.Replay(path)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if report.Checked != 2 || report.Skipped != 2 {
+		t.Errorf("Expected 2 checked and 2 skipped, got %d and %d", report.Checked, report.Skipped)
+	}
+	if len(report.Mismatches) != 1 {
+		t.Fatalf("Expected 1 mismatch, got %d", len(report.Mismatches))
+	}
+
+	m := report.Mismatches[0]
+	if m.Index != 1 || m.Stored != 99 || m.Recomputed != 14 {
+		t.Errorf("Expected mismatch at index 1 (99 vs 14), got %+v", m)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReplayReport(&buf, report); err != nil {
+		t.Fatalf("WriteReplayReport failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2 + 3 * 4: stored 99, recomputed 14") {
+		t.Errorf("Report missing mismatch line:\n%s", buf.String())
+	}
+}
+
+// TestReplaySkipsByOperation tests that entries are skipped by their
+// operation, not by symbols in the expression: a one-operand average such as
+// avg(5) has none of the symbols other non-infix entries have.
+func TestReplaySkipsByOperation(t *testing.T) {
+	s := newTestService(t, "")
+	path := filepath.Join(t.TempDir(), "replay.json")
+
+	h := history.NewHistory(path, 10)
+	h.AddSuccess("Average", "avg(5)", 5)
+	h.AddSuccess("Round", "round(2.5, 0)", 3)
+	h.AddSuccess("Quotient and Remainder", "divmod(7, 2)", 3)
+	h.AddSuccess("Factorial", "5!", 120)
+	h.AddSuccess("RPN", "5 3 *", 15)
+	h.AddSuccess("Multiplication", "5.00 * 3.00", 15)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	report, err := s.Replay(path)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if report.Checked != 1 || report.Skipped != 5 {
+		t.Errorf("Expected 1 checked and 5 skipped, got %d and %d", report.Checked, report.Skipped)
+	}
+	if len(report.Mismatches) != 0 {
+		t.Errorf("Expected no mismatches, got %+v", report.Mismatches)
+	}
+}
+
+// TestReplayMissingFile tests that replaying a nonexistent file is an error.
+func TestReplayMissingFile(t *testing.T) {
+	s := newTestService(t, "")
+
+	if _, err := s.Replay(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing history file")
+	}
+}
@@ -0,0 +1,101 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"cli-calculator/internal/validation"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rpnOperation is the history operation name for RPN expressions.
+const rpnOperation = "RPN"
+
+// RunRPN runs a reverse Polish notation REPL: each line such as "5 3 + 2 *"
+// is evaluated and printed until the user enters "q" or input ends.
+// This demonstrates a read-eval-print loop built on the existing input helpers.
+func (s *Service) RunRPN() error {
+	out := util.DefaultIO().Out
+	fmt.Fprintln(out, "RPN mode: enter numbers and operators separated by spaces (e.g. 5 3 + 2 *).")
+	fmt.Fprintln(out, "Enter q to quit.")
+
+	stopAutoSave := s.startAutoSave()
+	defer stopAutoSave()
+
+	for {
+		input, err := util.GetUserInput("rpn> ")
+		if err != nil {
+			if stderrors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch strings.ToLower(input) {
+		case "":
+			continue
+		case "q", "quit", "exit":
+			return nil
+		}
+
+		if err := s.evaluateRPNLine(input); err != nil {
+			util.PrintError(err)
+		}
+	}
+}
+
+// evaluateRPNLine evaluates one line of RPN input, prints it, and records it.
+func (s *Service) evaluateRPNLine(input string) error {
+	tokens, err := s.rpnTokens(input)
+	var evaluated calculator.Result
+	if err == nil {
+		evaluated, err = calculator.EvaluateRPNResult(tokens, s.calcOptions())
+	}
+	result := evaluated.Value
+	s.recordAudit(rpnOperation, input, result, err)
+	if err != nil {
+		if s.Config.SaveHistory {
+			s.History.AddError(rpnOperation, input, err)
+		}
+		return err
+	}
+
+	resultStr := s.formatCalculation(evaluated)
+	fmt.Fprintf(util.DefaultIO().Out, "= %s\n", resultStr)
+
+	if s.Config.SaveHistory {
+		s.History.AddSuccess(rpnOperation, input, calculator.RoundTo(result, s.Config.Precision))
+		s.saveAfterCalculation()
+	}
+
+	logger.Info("RPN evaluated: %s = %s", input, resultStr)
+	return nil
+}
+
+// rpnTokens splits an RPN line into tokens and validates each number like a
+// number typed at a prompt: the configured decimal separator, no inf or nan,
+// and the operand limits. Numbers are rewritten in the form the evaluator
+// parses; anything else is left for it to resolve as an operator.
+func (s *Service) rpnTokens(input string) ([]string, error) {
+	opts := s.calcOptions()
+	tokens := strings.Fields(input)
+	for i, token := range tokens {
+		if _, err := calculator.ResolveOperation(token); err == nil {
+			continue
+		}
+		if _, err := strconv.ParseFloat(token, 64); err != nil && !strings.ContainsAny(token, "0123456789") {
+			continue // Not a number at all, so report it as an unknown operator
+		}
+
+		value, err := validation.ValidateNumberInRange(token, s.Config.DecimalSeparator, opts.MinOperand, opts.MaxOperand)
+		if err != nil {
+			return nil, err
+		}
+		tokens[i] = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return tokens, nil
+}
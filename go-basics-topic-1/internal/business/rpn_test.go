@@ -0,0 +1,128 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/util"
+	"strings"
+	"testing"
+)
+
+// TestRunRPN tests the RPN REPL records each evaluated line.
+func TestRunRPN(t *testing.T) {
+	s := newTestService(t, "5 3 + 2 *\n5 +\n\n2 3 ^\nq\n")
+
+	if err := s.RunRPN(); err != nil {
+		t.Fatalf("RunRPN failed: %v", err)
+	}
+
+	entries := s.History.GetAll()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	tests := []struct {
+		expression string
+		success    bool
+		result     float64
+	}{
+		{"5 3 + 2 *", true, 16},
+		{"5 +", false, 0},
+		{"2 3 ^", true, 8},
+	}
+	for i, tt := range tests {
+		entry := entries[i]
+		if entry.Expression != tt.expression || entry.Success != tt.success || entry.Result != tt.result {
+			t.Errorf("entry %d: expected %q success=%v result=%v, got %q success=%v result=%v",
+				i, tt.expression, tt.success, tt.result, entry.Expression, entry.Success, entry.Result)
+		}
+	}
+}
+
+// TestRunRPNEndOfInput tests that the REPL exits cleanly when input ends.
+func TestRunRPNEndOfInput(t *testing.T) {
+	s := newTestService(t, "1 1 +\n")
+
+	if err := s.RunRPN(); err != nil {
+		t.Errorf("Expected clean exit at end of input, got %v", err)
+	}
+}
+
+// TestEvaluateRPNLineWholeNumbers tests that RPN results print like -expr
+// results: whole numbers without decimals unless force_decimals is set.
+func TestEvaluateRPNLineWholeNumbers(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		forceDecimals bool
+		expected      string
+	}{
+		{"whole number", "5 3 *", false, "= 15\n"},
+		{"fraction", "1 2 /", false, "= 0.50\n"},
+		{"force decimals", "5 3 *", true, "= 15.00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.ForceDecimals = tt.forceDecimals
+
+			var out bytes.Buffer
+			util.SetDefaultIO(util.NewIO(strings.NewReader(""), &out))
+
+			if err := s.evaluateRPNLine(tt.input); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, out.String())
+			}
+		})
+	}
+}
+
+// TestEvaluateRPNLineRejectsBadNumbers tests that numbers are validated like
+// prompt input, so nothing non-finite reaches history and saves keep working.
+func TestEvaluateRPNLineRejectsBadNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		separator string
+		expected  float64
+		hasError  bool
+	}{
+		{"infinity", "inf", ".", 0, true},
+		{"NaN", "nan 1 +", ".", 0, true},
+		{"single value above max", "1e16", ".", 0, true},
+		{"below min operand", "-5 3 +", ".", 0, true},
+		{"overflowing result", "1e15 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 * 1e15 *", ".", 0, true},
+		{"comma separator", "1,5 2 *", ",", 3, false},
+		{"unknown operator", "1 2 plux", ".", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+			s.Config.DecimalSeparator = tt.separator
+			s.Config.MinOperand = -1
+
+			err := s.evaluateRPNLine(tt.input)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+			} else if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			if err := s.History.Save(); err != nil {
+				t.Fatalf("%s: history no longer saves: %v", tt.name, err)
+			}
+			entries := s.History.GetAll()
+			if len(entries) != 1 || entries[0].Success == tt.hasError {
+				t.Fatalf("%s: expected 1 entry with success %v, got %+v", tt.name, !tt.hasError, entries)
+			}
+			if !tt.hasError && entries[0].Result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, entries[0].Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/logger"
+	"math/rand"
+)
+
+// maxSeedAttempts bounds how many random draws SeedHistory makes per entry,
+// so an operation that keeps failing (e.g. division by a zero draw) can't loop forever.
+const maxSeedAttempts = 10
+
+// RandSource creates the random source behind randomized features such as
+// SeedHistory. Production uses a time-seeded source: main passes
+// time.Now().UnixNano() when no -seed is given. Tests can replace RandSource
+// to inject a fixed sequence regardless of the seed passed in.
+var RandSource = rand.NewSource
+
+// SeedHistory records n random, valid calculations in history for demos and tests.
+// The same seed always produces the same calculations. It returns the number of
+// entries added, which is 0 for a read-only history. Seeded entries are not
+// saved until the next save.
+// This demonstrates reproducible randomness with math/rand and an explicit source.
+func (s *Service) SeedHistory(n int, seed int64) int {
+	if s.History.ReadOnly {
+		return 0
+	}
+
+	rng := rand.New(RandSource(seed))
+	added := 0
+
+	for i := 0; i < n; i++ {
+		for attempt := 0; attempt < maxSeedAttempts; attempt++ {
+			operation := constants.AllOperations[rng.Intn(len(constants.AllOperations))]
+			operands := randomOperands(rng, operation)
+
+			result, err := calculator.CalculateWithOptions(operation, operands, s.calcOptions())
+			if err != nil {
+				continue
+			}
+
+			s.History.AddSuccess(operation.String(), s.buildExpression(operation, operands), result.Value)
+			added++
+			break
+		}
+	}
+
+	logger.Debug("Seeded history with %d calculations (seed %d)", added, seed)
+	return added
+}
+
+// randomOperands draws small operands suited to the operation's arity and range.
+func randomOperands(rng *rand.Rand, operation constants.Operation) []float64 {
+	switch operation {
+	case constants.OpSquareRoot:
+		return []float64{float64(rng.Intn(100))}
+	case constants.OpFactorial:
+		return []float64{float64(rng.Intn(11))}
+	case constants.OpPower:
+		return []float64{float64(rng.Intn(10) + 1), float64(rng.Intn(6))}
+	case constants.OpRound:
+		return []float64{float64(rng.Intn(10000)) / 100, float64(rng.Intn(2))}
+	case constants.OpAverage:
+		return []float64{float64(rng.Intn(100)), float64(rng.Intn(100)), float64(rng.Intn(100))}
+	default:
+		return []float64{float64(rng.Intn(100)), float64(rng.Intn(100))}
+	}
+}
@@ -0,0 +1,71 @@
+package businessService
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSeedHistory tests that seeding adds the requested number of entries.
+func TestSeedHistory(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{"none", 0},
+		{"one", 1},
+		{"several", 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, "")
+
+			added := s.SeedHistory(tt.count, 42)
+			if added != tt.count {
+				t.Errorf("%s: expected %d added, got %d", tt.name, tt.count, added)
+			}
+			if s.History.Count() != tt.count {
+				t.Errorf("%s: expected History.Count() %d, got %d", tt.name, tt.count, s.History.Count())
+			}
+		})
+	}
+}
+
+// TestSeedHistoryDeterministic tests that the same seed produces the same entries.
+func TestSeedHistoryDeterministic(t *testing.T) {
+	first := newTestService(t, "")
+	second := newTestService(t, "")
+
+	first.SeedHistory(10, 7)
+	second.SeedHistory(10, 7)
+
+	a, b := first.History.GetAll(), second.History.GetAll()
+	for i := range a {
+		if a[i].Expression != b[i].Expression || a[i].Result != b[i].Result {
+			t.Errorf("entry %d: expected %s = %v, got %s = %v", i, a[i].Expression, a[i].Result, b[i].Expression, b[i].Result)
+		}
+	}
+}
+
+// TestSeedHistoryRandSource tests that an injected source makes seeding
+// reproducible even when callers pass different seeds.
+func TestSeedHistoryRandSource(t *testing.T) {
+	previous := RandSource
+	RandSource = func(int64) rand.Source { return rand.NewSource(1) }
+	t.Cleanup(func() { RandSource = previous })
+
+	first := newTestService(t, "")
+	second := newTestService(t, "")
+	first.SeedHistory(5, 100)
+	second.SeedHistory(5, 200)
+
+	a, b := first.History.GetAll(), second.History.GetAll()
+	if len(a) != 5 || len(b) != 5 {
+		t.Fatalf("Expected 5 entries each, got %d and %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].Expression != b[i].Expression || a[i].Result != b[i].Result {
+			t.Errorf("entry %d: expected %s = %v, got %s = %v", i, a[i].Expression, a[i].Result, b[i].Expression, b[i].Result)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package businessService
+
+import (
+	"cli-calculator/internal/calculator"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/util"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// runSweep asks for an operation, which operand to vary, and a range, then
+// writes a "what-if" table such as 2 ^ x for x from 1 to 10 to w. Sweep
+// results are not recorded in history, since one sweep can produce many rows.
+func (s *Service) runSweep(w io.Writer) error {
+	fmt.Println("WHAT-IF SWEEP:")
+	util.PrintDivider()
+	fmt.Println("Run one operation while one operand varies (e.g. 2 ^ x for x from 1 to 10).")
+
+	name, err := util.GetUserInput("Enter operation (e.g. ^, +, sqrt): ")
+	if err != nil {
+		return err
+	}
+	operation, err := calculator.ResolveOperation(name)
+	if err != nil {
+		return err
+	}
+
+	fixed, varyIndex, err := s.readSweepOperands(operation)
+	if err != nil {
+		return err
+	}
+
+	from, err := s.readNumber("Enter start value for x: ")
+	if err != nil {
+		return err
+	}
+	to, err := s.readNumber("Enter end value for x: ")
+	if err != nil {
+		return err
+	}
+	step, err := s.readNumber("Enter step: ")
+	if err != nil {
+		return err
+	}
+
+	results, err := calculator.SweepWithOptions(operation, fixed, varyIndex, from, to, step, s.calcOptions())
+	if err != nil {
+		return err
+	}
+
+	header := sweepExpression(operation, fixed, varyIndex)
+	var b strings.Builder
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%15s  %s\n", "x", header)
+	fmt.Fprintln(&b, strings.Repeat("═", 56))
+	for _, result := range results {
+		fmt.Fprintf(&b, "%15s  %s\n", s.formatResult(result.Operands[varyIndex]), s.formatResult(result.Value))
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	logger.Info("Sweep of %s over %d values", header, len(results))
+	return nil
+}
+
+// readSweepOperands asks which operand varies and reads the fixed one.
+// Square root and factorial have a single operand, which is the one that varies.
+func (s *Service) readSweepOperands(operation constants.Operation) ([]float64, int, error) {
+	if operation == constants.OpSquareRoot || operation == constants.OpFactorial {
+		return nil, 0, nil
+	}
+
+	which, err := util.GetUserInput("Vary which operand? (1 = first, 2 = second): ")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch which {
+	case "1":
+		second, err := s.readNumber("Enter second number: ")
+		if err != nil {
+			return nil, 0, err
+		}
+		return []float64{second}, 0, nil
+	case "2":
+		first, err := s.readNumber("Enter first number: ")
+		if err != nil {
+			return nil, 0, err
+		}
+		return []float64{first}, 1, nil
+	default:
+		return nil, 0, errors.NewValidationError("operand", which, "must be 1 or 2")
+	}
+}
+
+// sweepExpression renders the swept calculation with x in place of the
+// varying operand, e.g. "2 ^ x" or "√x".
+func sweepExpression(operation constants.Operation, fixed []float64, varyIndex int) string {
+	switch operation {
+	case constants.OpSquareRoot:
+		return "√x"
+	case constants.OpFactorial:
+		return "x!"
+	}
+
+	terms := []string{"x", plainNumber(fixed[0])}
+	if varyIndex == 1 {
+		terms[0], terms[1] = terms[1], terms[0]
+	}
+	return fmt.Sprintf("%s %s %s", terms[0], operation.Symbol(), terms[1])
+}
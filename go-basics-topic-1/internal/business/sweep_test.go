@@ -0,0 +1,74 @@
+package businessService
+
+import (
+	"bytes"
+	"cli-calculator/internal/constants"
+	"strings"
+	"testing"
+)
+
+// TestRunSweep tests the what-if menu flow and its printed table.
+func TestRunSweep(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		hasError bool
+	}{
+		{"power of two", "^\n2\n2\n1\n4\n1\n", []string{"2 ^ x", "16.00"}, false},
+		{"vary first operand", "*\n1\n10\n1\n3\n1\n", []string{"x * 10", "30.00"}, false},
+		{"single operand", "sqrt\n1\n9\n8\n", []string{"√x", "3.00"}, false},
+		{"zero step", "^\n2\n2\n1\n4\n0\n", nil, true},
+		{"bad operand choice", "^\n3\n", nil, true},
+		{"unknown operation", "log\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, tt.input)
+			var out bytes.Buffer
+
+			err := s.runSweep(&out)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("%s: expected %q in output:\n%s", tt.name, want, out.String())
+				}
+			}
+			if s.History.Count() != 0 {
+				t.Errorf("%s: expected sweeps not to be recorded, got %d entries", tt.name, s.History.Count())
+			}
+		})
+	}
+}
+
+// TestSweepExpression tests the table header for each operand position.
+func TestSweepExpression(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		fixed     []float64
+		varyIndex int
+		expected  string
+	}{
+		{"vary exponent", constants.OpPower, []float64{2}, 1, "2 ^ x"},
+		{"vary base", constants.OpPower, []float64{2}, 0, "x ^ 2"},
+		{"factorial", constants.OpFactorial, nil, 0, "x!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sweepExpression(tt.operation, tt.fixed, tt.varyIndex); result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSuggestionDistance is how many single-character edits an alias may be
+// from a known alias and still be suggested.
+const maxSuggestionDistance = 2
+
+// ResolveOperation resolves a symbol or word (e.g. "plus", "+") to an operation.
+// Unknown names return a ValidationError that lists close matches, or the
+// known operation names when nothing is close.
+func ResolveOperation(name string) (constants.Operation, error) {
+	if op, ok := constants.LookupOperation(name); ok {
+		return op, nil
+	}
+
+	suggestions := SuggestOperations(name)
+	message := "unknown operation"
+	if len(suggestions) > 0 {
+		message = fmt.Sprintf("unknown operation; did you mean: %s?", strings.Join(suggestions, ", "))
+	}
+	return constants.OpUnknown, errors.NewValidationError("operation", name, message)
+}
+
+// ExplainOperation resolves name like ResolveOperation and describes the
+// operation for learners, e.g.
+// "modulo: remainder of division; 10 % 3 = 1 (complexity: O(1))".
+func ExplainOperation(name string) (string, error) {
+	op, err := ResolveOperation(name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s: %s (complexity: %s)",
+		strings.ToLower(op.String()), constants.OperationDescription(op), constants.OperationComplexity(op)), nil
+}
+
+// SuggestOperations returns known word aliases close to name, sorted by
+// closeness and then alphabetically. When none are close it falls back to
+// the lower-case names of all operations.
+func SuggestOperations(name string) []string {
+	target := strings.ToLower(strings.TrimSpace(name))
+
+	type candidate struct {
+		alias    string
+		distance int
+	}
+	candidates := make([]candidate, 0)
+	for alias := range constants.OperationAliases {
+		// Symbols are too short for edit distance to be meaningful
+		if utf8.RuneCountInString(alias) < 2 {
+			continue
+		}
+		if d := editDistance(target, alias); d <= maxSuggestionDistance {
+			candidates = append(candidates, candidate{alias, d})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].alias < candidates[j].alias
+	})
+
+	suggestions := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		suggestions = append(suggestions, c.alias)
+	}
+	if len(suggestions) > 0 {
+		return suggestions
+	}
+
+	// Nothing close: list every operation by name instead
+	for _, op := range constants.AllOperations {
+		suggestions = append(suggestions, strings.ToLower(strings.ReplaceAll(op.String(), " ", "")))
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+// This demonstrates dynamic programming with a single reusable row.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0] // Value of row[j-1] from the previous iteration
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			current := row[j]
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			row[j] = min(row[j]+1, row[j-1]+1, prev+cost)
+			prev = current
+		}
+	}
+
+	return row[len(rb)]
+}
@@ -0,0 +1,163 @@
+// Package calculator provides operation alias resolution with tests.
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"strings"
+	"testing"
+)
+
+// TestResolveOperation tests several aliases per operation.
+func TestResolveOperation(t *testing.T) {
+	tests := []struct {
+		expected constants.Operation
+		aliases  []string
+	}{
+		{constants.OpAddition, []string{"+", "add", "plus", "sum", "ADD"}},
+		{constants.OpSubtraction, []string{"-", "sub", "minus", "subtract"}},
+		{constants.OpMultiplication, []string{"*", "x", "times", "multiply"}},
+		{constants.OpDivision, []string{"/", "div", "divide", " over "}},
+		{constants.OpPower, []string{"^", "pow", "power"}},
+		{constants.OpSquareRoot, []string{"√", "sqrt", "root"}},
+		{constants.OpModulo, []string{"%", "mod", "remainder"}},
+		{constants.OpFactorial, []string{"!", "fact", "factorial"}},
+		{constants.OpPercentChange, []string{"pct", "change", "percentchange"}},
+		{constants.OpRatio, []string{":", "ratio"}},
+	}
+
+	for _, tt := range tests {
+		for _, alias := range tt.aliases {
+			t.Run(alias, func(t *testing.T) {
+				op, err := ResolveOperation(alias)
+				if err != nil {
+					t.Fatalf("%q: unexpected error: %v", alias, err)
+				}
+				if op != tt.expected {
+					t.Errorf("%q: expected %v, got %v", alias, tt.expected, op)
+				}
+			})
+		}
+	}
+}
+
+// TestResolveOperationUnknown tests that unknown aliases list suggestions.
+func TestResolveOperationUnknown(t *testing.T) {
+	tests := []struct {
+		name    string
+		alias   string
+		suggest string
+	}{
+		{"typo", "plux", "plus"},
+		{"close to two aliases", "mu", "mul"},
+		{"nothing close", "logarithm", "addition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := ResolveOperation(tt.alias)
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tt.name)
+			}
+			if op != constants.OpUnknown {
+				t.Errorf("%s: expected OpUnknown, got %v", tt.name, op)
+			}
+			if !strings.Contains(err.Error(), tt.suggest) {
+				t.Errorf("%s: expected suggestion %q in %q", tt.name, tt.suggest, err.Error())
+			}
+		})
+	}
+}
+
+// TestEditDistance tests the Levenshtein distance helper.
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"add", "add", 0},
+		{"plux", "plus", 1},
+		{"", "sum", 3},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if d := editDistance(tt.a, tt.b); d != tt.expected {
+			t.Errorf("editDistance(%q, %q): expected %d, got %d", tt.a, tt.b, tt.expected, d)
+		}
+	}
+}
+
+// TestExplainOperation tests descriptions for several operations and an unknown name.
+func TestExplainOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"modulo by name", "modulo", "modulo: remainder of division; 10 % 3 = 1 (complexity: O(1))", false},
+		{"factorial by symbol", "!", "factorial: product of all whole numbers from 1 to n; 5! = 120 (complexity: O(n) multiplications)", false},
+		{"division by alias", "divide", "division: quotient of two numbers (the divisor cannot be zero); 10 / 4 = 2.5 (complexity: O(1))", false},
+		{"unknown name", "logarithm", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExplainOperation(tt.input)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %q", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestOperationDescriptionCoversAllOperations tests that no operation lacks a description.
+func TestOperationDescriptionCoversAllOperations(t *testing.T) {
+	for _, op := range constants.AllOperations {
+		if constants.OperationDescription(op) == "" {
+			t.Errorf("%v: missing description", op)
+		}
+	}
+	if constants.OperationDescription(constants.OpUnknown) != "" {
+		t.Error("Expected no description for OpUnknown")
+	}
+}
+
+// TestOperationComplexity tests the complexity notes shown in help and -explain.
+func TestOperationComplexity(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		expected  string
+	}{
+		{"addition", constants.OpAddition, "O(n) in the number of operands"},
+		{"division", constants.OpDivision, "O(1)"},
+		{"factorial", constants.OpFactorial, "O(n) multiplications"},
+		{"ratio", constants.OpRatio, "O(log n) (Euclid's gcd)"},
+		{"unknown", constants.OpUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := constants.OperationComplexity(tt.operation); result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+
+	for _, op := range constants.AllOperations {
+		if constants.OperationComplexity(op) == "" {
+			t.Errorf("%v: missing complexity", op)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"testing"
+)
+
+// BenchmarkResult holds the runtime cost of one operation.
+type BenchmarkResult struct {
+	Operation  constants.Operation // The operation measured
+	Iterations int                 // How many times it ran
+	NsPerOp    int64               // Average nanoseconds per call
+}
+
+// benchmarkOperands returns representative operands for an operation.
+// Factorial uses a larger input so its O(n) loop is visible next to O(1) operations.
+func benchmarkOperands(operation constants.Operation) []float64 {
+	switch operation {
+	case constants.OpSquareRoot:
+		return []float64{12345.678}
+	case constants.OpFactorial:
+		return []float64{170}
+	case constants.OpRound:
+		return []float64{123.456789, 2}
+	case constants.OpAverage:
+		return []float64{123.456, 7.89, 42.5, 0.125}
+	default:
+		return []float64{123.456, 7.89}
+	}
+}
+
+// RunBenchmarks measures every operation at runtime using testing.Benchmark,
+// the same machinery behind `go test -bench`. This lets learners compare the
+// relative cost of operations from a built binary.
+func RunBenchmarks() []BenchmarkResult {
+	results := make([]BenchmarkResult, 0, len(constants.AllOperations))
+
+	for _, operation := range constants.AllOperations {
+		operands := benchmarkOperands(operation)
+		op := operation // Capture for the closure
+
+		r := testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Calculate(op, operands)
+			}
+		})
+
+		results = append(results, BenchmarkResult{
+			Operation:  operation,
+			Iterations: r.N,
+			NsPerOp:    r.NsPerOp(),
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,38 @@
+// Package calculator provides the runtime benchmark runner with tests.
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"flag"
+	"testing"
+)
+
+// TestRunBenchmarks tests that every operation gets a non-zero timing.
+func TestRunBenchmarks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmark runner in short mode")
+	}
+
+	// Keep the runner fast; the default is one second per operation
+	benchtime := flag.Lookup("test.benchtime")
+	previous := benchtime.Value.String()
+	benchtime.Value.Set("10ms")
+	defer benchtime.Value.Set(previous)
+
+	results := RunBenchmarks()
+
+	if len(results) != len(constants.AllOperations) {
+		t.Fatalf("Expected %d results, got %d", len(constants.AllOperations), len(results))
+	}
+	for i, result := range results {
+		if result.Operation != constants.AllOperations[i] {
+			t.Errorf("Result %d: expected %v, got %v", i, constants.AllOperations[i], result.Operation)
+		}
+		if result.Iterations <= 0 {
+			t.Errorf("%v: expected iterations > 0, got %d", result.Operation, result.Iterations)
+		}
+		if result.NsPerOp <= 0 {
+			t.Errorf("%v: expected ns/op > 0, got %d", result.Operation, result.NsPerOp)
+		}
+	}
+}
@@ -7,13 +7,111 @@ import (
 	"cli-calculator/internal/errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
+// maxExactInteger is the largest integer float64 can represent without gaps (2^53).
+const maxExactInteger = 1 << 53
+
+// Result holds a calculation outcome together with the inputs that produced it.
+// This demonstrates returning a struct to keep related values together.
+type Result struct {
+	Value     float64             // The computed value
+	Operation constants.Operation // The operation performed
+	Operands  []float64           // The operands used (a copy of the input)
+	Exact     bool                // True when no floating-point rounding could have occurred
+}
+
+// Options holds configurable limits for calculations.
+// Start from DefaultOptions and override individual fields.
+type Options struct {
+	MaxOperand        float64 // Operands must lie within [-MaxOperand, MaxOperand]
+	MinOperand        float64 // Typed numbers below this floor are rejected (e.g. 0 forbids negatives); intermediate results are not
+	MaxFactorialInput int     // Largest n accepted by factorial; 0 means constants.MaxFactorialInput
+	ModuloMode        string  // constants.ModuloTruncated (also used when empty) or constants.ModuloEuclidean
+}
+
+// DefaultOptions returns the options used by Calculate and CalculateResult.
+func DefaultOptions() Options {
+	return Options{
+		MaxOperand:        constants.MaxNumberInputValue,
+		MinOperand:        constants.MinNumberInputValue,
+		MaxFactorialInput: constants.MaxFactorialInput,
+		ModuloMode:        constants.ModuloTruncated,
+	}
+}
+
 // Calculate performs a calculation based on the operation and operands.
+// It is a thin wrapper around CalculateResult for callers that only need the value.
 // This demonstrates function parameters, return values, and error handling.
 func Calculate(operation constants.Operation, operands []float64) (float64, error) {
+	result, err := CalculateResult(operation, operands)
+	if err != nil {
+		return 0, err
+	}
+	return result.Value, nil
+}
+
+// CalculateResult performs a calculation and returns a Result describing it.
+func CalculateResult(operation constants.Operation, operands []float64) (Result, error) {
+	return CalculateWithOptions(operation, operands, DefaultOptions())
+}
+
+// CalculateWithOptions performs a calculation using the given limits.
+func CalculateWithOptions(operation constants.Operation, operands []float64, opts Options) (Result, error) {
+	value, err := calculate(operation, operands, opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Results such as 1e15 multiplied many times can overflow float64; an
+	// infinite value can't be shown sensibly or saved to JSON history
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return Result{}, errors.NewCalculationError(
+			operation.String(),
+			operands,
+			"result is not a finite number (too large)",
+			errors.ErrOutOfRange,
+		)
+	}
+
+	// Copy operands so later changes by the caller don't alter the result
+	copied := make([]float64, len(operands))
+	copy(copied, operands)
+
+	return Result{
+		Value:     value,
+		Operation: operation,
+		Operands:  copied,
+		Exact:     isExact(value, operands),
+	}, nil
+}
+
+// isExact reports whether a calculation was free of rounding error.
+// Arithmetic on whole numbers that float64 represents exactly is exact as long
+// as the result is also such a whole number; anything else may have been rounded.
+func isExact(value float64, operands []float64) bool {
+	if !isExactInteger(value) {
+		return false
+	}
+	for _, operand := range operands {
+		if !isExactInteger(operand) {
+			return false
+		}
+	}
+	return true
+}
+
+// isExactInteger reports whether v is a whole number within ±2^53.
+func isExactInteger(v float64) bool {
+	return v == math.Trunc(v) && math.Abs(v) <= maxExactInteger
+}
+
+// calculate validates the input and dispatches to the operation's implementation.
+func calculate(operation constants.Operation, operands []float64, opts Options) (float64, error) {
 	// Validate operation and operands
-	if err := validateCalculation(operation, operands); err != nil {
+	if err := validateCalculation(operation, operands, opts); err != nil {
 		return 0, err
 	}
 
@@ -26,15 +124,23 @@ func Calculate(operation constants.Operation, operands []float64) (float64, erro
 	case constants.OpMultiplication:
 		return multiply(operands), nil
 	case constants.OpDivision:
-		return divide(operands[0], operands[1])
+		return divideAll(operands)
 	case constants.OpPower:
-		return power(operands[0], operands[1]), nil
+		return power(operands[0], operands[1])
 	case constants.OpSquareRoot:
 		return squareRoot(operands[0])
 	case constants.OpModulo:
-		return modulo(operands[0], operands[1])
+		return modulo(operands[0], operands[1], opts.ModuloMode)
 	case constants.OpFactorial:
-		return factorial(operands[0])
+		return factorial(operands[0], opts.MaxFactorialInput)
+	case constants.OpPercentChange:
+		return percentChange(operands[0], operands[1])
+	case constants.OpRatio:
+		return ratioValue(operands[0], operands[1])
+	case constants.OpRound:
+		return round(operands[0], operands[1])
+	case constants.OpAverage:
+		return mean(operands), nil
 	default:
 		return 0, errors.NewCalculationError(
 			operation.String(),
@@ -46,7 +152,7 @@ func Calculate(operation constants.Operation, operands []float64) (float64, erro
 }
 
 // validateCalculation validates the operation and operands.
-func validateCalculation(operation constants.Operation, operands []float64) error {
+func validateCalculation(operation constants.Operation, operands []float64, opts Options) error {
 	// Check if we have operands
 	if len(operands) == 0 {
 		return errors.NewValidationError("operands", "none", "at least one operand is required")
@@ -62,7 +168,13 @@ func validateCalculation(operation constants.Operation, operands []float64) erro
 		)
 	}
 
-	// Validate operand ranges
+	return validateOperands(operands, opts)
+}
+
+// validateOperands rejects NaN, infinite, and out-of-range operands.
+// The MinOperand floor is left to validateMinOperand, since operands here may
+// be intermediate results (3 - 5 in 3 - 5 + 4) rather than typed numbers.
+func validateOperands(operands []float64, opts Options) error {
 	for i, val := range operands {
 		if math.IsNaN(val) {
 			return errors.NewValidationError(
@@ -78,11 +190,26 @@ func validateCalculation(operation constants.Operation, operands []float64) erro
 				"operand cannot be infinity",
 			)
 		}
-		if val > constants.MaxNumberInputValue || val < constants.MinNumberInputValue {
+		if val > opts.MaxOperand || val < -opts.MaxOperand {
 			return errors.NewValidationError(
 				fmt.Sprintf("operand[%d]", i),
 				fmt.Sprintf("%f", val),
-				fmt.Sprintf("operand must be between %e and %e", constants.MinNumberInputValue, constants.MaxNumberInputValue),
+				fmt.Sprintf("operand must be between %g and %g", -opts.MaxOperand, opts.MaxOperand),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateMinOperand rejects typed numbers below the MinOperand floor.
+func validateMinOperand(operands []float64, opts Options) error {
+	for i, val := range operands {
+		if val < opts.MinOperand {
+			return errors.NewValidationError(
+				fmt.Sprintf("operand[%d]", i),
+				fmt.Sprintf("%f", val),
+				fmt.Sprintf("operand must be at least %g", opts.MinOperand),
 			)
 		}
 	}
@@ -95,7 +222,7 @@ func getRequiredOperandCount(operation constants.Operation) int {
 	switch operation {
 	case constants.OpSquareRoot, constants.OpFactorial:
 		return 1
-	case constants.OpAddition, constants.OpSubtraction, constants.OpMultiplication:
+	case constants.OpAddition, constants.OpSubtraction, constants.OpMultiplication, constants.OpAverage:
 		return 1 // Can work with 1+ operands
 	default:
 		return 2 // Binary operations
@@ -114,7 +241,8 @@ func add(operands []float64) float64 {
 	return result
 }
 
-// subtract subtracts subsequent numbers from the first.
+// subtract subtracts subsequent numbers from the first, folding left:
+// 10 - 3 - 2 is (10 - 3) - 2 = 5, and a single operand is returned as is.
 func subtract(operands []float64) float64 {
 	if len(operands) == 0 {
 		return 0
@@ -130,6 +258,12 @@ func subtract(operands []float64) float64 {
 	return result
 }
 
+// mean returns the arithmetic mean of the operands. Callers must pass at
+// least one operand; validateCalculation rejects an empty list.
+func mean(operands []float64) float64 {
+	return add(operands) / float64(len(operands))
+}
+
 // multiply multiplies multiple numbers together.
 func multiply(operands []float64) float64 {
 	if len(operands) == 0 {
@@ -143,6 +277,20 @@ func multiply(operands []float64) float64 {
 	return result
 }
 
+// divideAll divides the first number by each of the rest, folding left like
+// subtract: 100 / 5 / 2 is (100 / 5) / 2 = 10. A zero anywhere after the
+// first operand is a division by zero.
+func divideAll(operands []float64) (float64, error) {
+	result := operands[0]
+	for _, divisor := range operands[1:] {
+		var err error
+		if result, err = divide(result, divisor); err != nil {
+			return 0, err
+		}
+	}
+	return result, nil
+}
+
 // divide divides the first number by the second.
 // This demonstrates error handling for invalid operations.
 func divide(a, b float64) (float64, error) {
@@ -174,8 +322,19 @@ func divide(a, b float64) (float64, error) {
 // Advanced operations
 
 // power raises a to the power of b.
-func power(a, b float64) float64 {
-	return math.Pow(a, b)
+// Results that overflow to infinity or exceed constants.MaxNumberInputValue
+// are rejected rather than returned as +Inf.
+func power(a, b float64) (float64, error) {
+	result := math.Pow(a, b)
+	if math.IsInf(result, 0) || math.Abs(result) > constants.MaxNumberInputValue {
+		return 0, errors.NewCalculationError(
+			"Power",
+			[]float64{a, b},
+			"result would overflow (too large)",
+			errors.ErrOutOfRange,
+		)
+	}
+	return result, nil
 }
 
 // squareRoot calculates the square root of a number.
@@ -192,7 +351,9 @@ func squareRoot(a float64) (float64, error) {
 }
 
 // modulo calculates the remainder of a divided by b.
-func modulo(a, b float64) (float64, error) {
+// Truncated mode matches math.Mod, so the remainder takes the sign of a.
+// Euclidean mode always returns a remainder in [0, |b|).
+func modulo(a, b float64, mode string) (float64, error) {
 	if b == 0 {
 		return 0, errors.NewCalculationError(
 			"Modulo",
@@ -201,11 +362,41 @@ func modulo(a, b float64) (float64, error) {
 			errors.ErrDivisionByZero,
 		)
 	}
-	return math.Mod(a, b), nil
+	r := math.Mod(a, b)
+	if mode == constants.ModuloEuclidean && r < 0 {
+		r += math.Abs(b)
+		// A tiny negative remainder can round up to |b| itself
+		if r == math.Abs(b) {
+			r = 0
+		}
+	}
+	return r, nil
+}
+
+// DivMod returns the whole-number quotient of a divided by b and the
+// remainder left over, so that a == quotient*b + remainder; 17 / 5 gives
+// 3 remainder 2. The quotient is truncated toward zero, so the remainder
+// takes the sign of a, like math.Mod and truncated modulo.
+func DivMod(a, b float64) (quotient float64, remainder float64, err error) {
+	if b == 0 {
+		return 0, 0, errors.NewCalculationError(
+			"DivMod",
+			[]float64{a, b},
+			"division by zero in quotient and remainder",
+			errors.ErrDivisionByZero,
+		)
+	}
+	remainder = math.Mod(a, b)
+	// (a - remainder) / b is a whole number; rounding absorbs float error
+	// that math.Trunc(a / b) could turn into an off-by-one quotient
+	quotient = math.Round((a - remainder) / b)
+	return quotient, remainder, nil
 }
 
 // factorial calculates the factorial of a number.
-func factorial(n float64) (float64, error) {
+// Inputs above limit are rejected; a limit of 0 (unset) or above
+// constants.MaxFactorialInput falls back to that float64 overflow bound.
+func factorial(n float64, limit int) (float64, error) {
 	// Check if n is an integer
 	if n != math.Floor(n) {
 		return 0, errors.NewCalculationError(
@@ -227,7 +418,7 @@ func factorial(n float64) (float64, error) {
 	}
 
 	// Check for overflow (factorial grows very quickly)
-	if n > 170 {
+	if n > constants.MaxFactorialInput {
 		return 0, errors.NewCalculationError(
 			"Factorial",
 			[]float64{n},
@@ -236,6 +427,16 @@ func factorial(n float64) (float64, error) {
 		)
 	}
 
+	// Check the configured ceiling
+	if limit > 0 && n > float64(limit) {
+		return 0, errors.NewCalculationError(
+			"Factorial",
+			[]float64{n},
+			fmt.Sprintf("factorial input must be at most %d", limit),
+			errors.ErrOutOfRange,
+		)
+	}
+
 	// Calculate factorial iteratively
 	result := 1.0
 	for i := 2.0; i <= n; i++ {
@@ -245,9 +446,55 @@ func factorial(n float64) (float64, error) {
 	return result, nil
 }
 
+// AlmostEqual reports whether a and b differ by no more than epsilon.
+// Exact float comparison is brittle (0.1+0.2 != 0.3), so results should be
+// compared with a tolerance instead.
+func AlmostEqual(a, b, epsilon float64) bool {
+	// Identical values (including matching infinities) are always equal
+	if a == b {
+		return true
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return false
+	}
+	return math.Abs(a-b) <= epsilon
+}
+
+// FormatOptions controls how results are rendered.
+type FormatOptions struct {
+	Precision         int    // Number of decimal places
+	DecimalSeparator  string // Decimal separator; empty means "."
+	TrimTrailingZeros bool   // Drop trailing zeros after rounding, so 8.00 prints as 8 and 3.140 as 3.14
+	WholeNumber       bool   // Print whole-number results without decimals whatever the precision; set from Result.Exact
+}
+
+// precisionFormats caches the format verb for each valid precision ("%.0f" to
+// "%.15f") so formatting in a loop doesn't rebuild it on every call.
+var precisionFormats = func() [16]string {
+	var formats [16]string
+	for p := range formats {
+		formats[p] = fmt.Sprintf("%%.%df", p)
+	}
+	return formats
+}()
+
+// precisionFormat returns the format verb for a precision, building it only
+// for values outside the cached 0-15 range.
+func precisionFormat(precision int) string {
+	if precision >= 0 && precision < len(precisionFormats) {
+		return precisionFormats[precision]
+	}
+	return fmt.Sprintf("%%.%df", precision)
+}
+
 // FormatResult formats a calculation result with the specified precision.
 // This demonstrates string formatting and type conversion.
 func FormatResult(result float64, precision int) string {
+	return FormatResultWith(result, FormatOptions{Precision: precision})
+}
+
+// FormatResultWith formats a calculation result using the given options.
+func FormatResultWith(result float64, opts FormatOptions) string {
 	// Handle special cases
 	if math.IsNaN(result) {
 		return "NaN"
@@ -259,7 +506,203 @@ func FormatResult(result float64, precision int) string {
 		return "-Inf"
 	}
 
-	// Format with specified precision
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, result)
+	// Format with specified precision; exact whole numbers need no decimals
+	precision := opts.Precision
+	if opts.WholeNumber && isExactInteger(result) {
+		precision = 0
+	}
+	formatted := fmt.Sprintf(precisionFormat(precision), result)
+	if opts.TrimTrailingZeros {
+		formatted = trimTrailingZeros(formatted)
+	}
+
+	// Swap in a locale-specific decimal separator
+	if opts.DecimalSeparator != "" && opts.DecimalSeparator != constants.DefaultDecimalSeparator {
+		formatted = strings.Replace(formatted, constants.DefaultDecimalSeparator, opts.DecimalSeparator, 1)
+	}
+	return formatted
+}
+
+// trimTrailingZeros removes zeros after the decimal point, and the point
+// itself when nothing is left after it. A value that rounded to zero from
+// below ("-0.00") becomes "0" rather than "-0".
+func trimTrailingZeros(formatted string) string {
+	if !strings.Contains(formatted, ".") {
+		return formatted
+	}
+	trimmed := strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+	if trimmed == "-0" {
+		return "0"
+	}
+	return trimmed
+}
+
+// RoundTo rounds result to the given number of decimal places exactly as
+// FormatResult displays it, so a stored value matches what the user saw.
+// Values that don't format as numbers (NaN, ±Inf) are returned unchanged.
+func RoundTo(result float64, precision int) float64 {
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return result
+	}
+
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(result, 'f', precision, 64), 64)
+	if err != nil {
+		return result
+	}
+	return rounded
+}
+
+// round rounds value to decimals decimal places, with halves rounded away
+// from zero (round(2.5, 0) = 3). Unlike RoundTo it rounds the decimal the
+// user typed: the value is scaled by moving its decimal exponent, which is
+// exact, rather than multiplying by 10^decimals, so round(1.005, 2) is 1.01
+// even though 1.005 is stored as 1.00499999...
+func round(value, decimals float64) (float64, error) {
+	if decimals < 0 || decimals != math.Trunc(decimals) || decimals > constants.MaxRoundDecimals {
+		return 0, errors.NewCalculationError(
+			"Round",
+			[]float64{value, decimals},
+			fmt.Sprintf("decimal places must be a whole number between 0 and %d", constants.MaxRoundDecimals),
+			errors.ErrInvalidInput,
+		)
+	}
+	places := int(decimals)
+
+	// Shortest decimal form, e.g. 1.005 -> "1.005e+00", then shift to "1.005e+02"
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(value, 'e', -1, 64), "e")
+	exp, err := strconv.Atoi(exponent)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to scale value for rounding")
+	}
+	scaled, err := strconv.ParseFloat(fmt.Sprintf("%se%d", mantissa, exp+places), 64)
+	if err != nil {
+		return 0, errors.NewCalculationError("Round", []float64{value, decimals}, "value too large to round", errors.ErrOutOfRange)
+	}
+
+	// Shift the rounded whole number back the same way
+	rounded := strconv.FormatFloat(math.Round(scaled), 'f', 0, 64)
+	result, err := strconv.ParseFloat(fmt.Sprintf("%se-%d", rounded, places), 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to scale rounded value")
+	}
+	if result == 0 {
+		return 0, nil // Avoid -0 for small negative inputs
+	}
+	return result, nil
+}
+
+// Finance operations
+
+// percentChange returns the percentage change from a to b: (b-a)/a*100.
+func percentChange(a, b float64) (float64, error) {
+	if a == 0 {
+		return 0, errors.NewCalculationError(
+			"PercentChange",
+			[]float64{a, b},
+			"percent change from zero is undefined",
+			errors.ErrDivisionByZero,
+		)
+	}
+	return (b - a) / a * 100, nil
+}
+
+// ratioValue returns the ratio a:b as a single number (a/b).
+func ratioValue(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, errors.NewCalculationError(
+			"Ratio",
+			[]float64{a, b},
+			"ratio with a zero second term is undefined",
+			errors.ErrDivisionByZero,
+		)
+	}
+	return a / b, nil
+}
+
+// CompoundInterest returns the final amount of principal p invested at annual
+// rate r (0.05 for 5%), compounded n times per year for t years:
+// p * (1 + r/n)^(n*t). Negative inputs and n = 0 are rejected.
+func CompoundInterest(p, r, n, t float64) (float64, error) {
+	inputs := []float64{p, r, n, t}
+	if err := validateOperands(inputs, DefaultOptions()); err != nil {
+		return 0, err
+	}
+	if p < 0 || r < 0 || n < 0 || t < 0 {
+		return 0, errors.NewCalculationError(
+			"CompoundInterest",
+			inputs,
+			"principal, rate, periods, and years cannot be negative",
+			errors.ErrInvalidInput,
+		)
+	}
+	if n == 0 {
+		return 0, errors.NewCalculationError(
+			"CompoundInterest",
+			inputs,
+			"interest must be compounded at least once per year",
+			errors.ErrDivisionByZero,
+		)
+	}
+
+	amount := p * math.Pow(1+r/n, n*t)
+	if math.IsInf(amount, 0) {
+		return 0, errors.NewCalculationError(
+			"CompoundInterest",
+			inputs,
+			"result is infinity (overflow)",
+			errors.ErrOutOfRange,
+		)
+	}
+
+	return amount, nil
+}
+
+// maxRatioDecimals limits how far Ratio scales decimal terms to whole numbers.
+const maxRatioDecimals = 6
+
+// Ratio returns a:b reduced to lowest terms, e.g. Ratio(4, 6) is "2:3".
+// Decimal terms are first scaled to whole numbers (1.5:2 becomes 3:4).
+// This demonstrates Euclid's algorithm for the greatest common divisor.
+func Ratio(a, b float64) (string, error) {
+	if _, err := ratioValue(a, b); err != nil {
+		return "", err
+	}
+
+	// Scale both terms by 10 until they are whole numbers
+	for i := 0; i < maxRatioDecimals && !(isExactInteger(a) && isExactInteger(b)); i++ {
+		a, b = a*10, b*10
+	}
+	if !isExactInteger(a) || !isExactInteger(b) {
+		return "", errors.NewCalculationError(
+			"Ratio",
+			[]float64{a, b},
+			fmt.Sprintf("terms must have at most %d decimal places", maxRatioDecimals),
+			errors.ErrInvalidInput,
+		)
+	}
+
+	x, y := int64(math.Round(a)), int64(math.Round(b))
+	if y < 0 {
+		// Keep any sign on the first term
+		x, y = -x, -y
+	}
+	if d := gcd(x, y); d > 1 {
+		x, y = x/d, y/d
+	}
+
+	return fmt.Sprintf("%d:%d", x, y), nil
+}
+
+// gcd returns the greatest common divisor of |a| and |b|.
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
 }
@@ -4,7 +4,11 @@ package calculator
 
 import (
 	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"fmt"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -31,6 +35,79 @@ func TestCalculateSubtraction(t *testing.T) {
 	}
 }
 
+// TestCalculateMultiOperandLeftFold tests that subtraction and division
+// with three or more operands apply left to right.
+func TestCalculateMultiOperandLeftFold(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		operands  []float64
+		expected  float64
+		hasError  bool
+	}{
+		{"subtract three", constants.OpSubtraction, []float64{10, 3, 2}, 5, false},
+		{"subtract four", constants.OpSubtraction, []float64{100, 50, 25, 5}, 20, false},
+		{"subtract negatives", constants.OpSubtraction, []float64{10, -3, 2}, 11, false},
+		{"subtract single", constants.OpSubtraction, []float64{7}, 7, false},
+		{"divide three", constants.OpDivision, []float64{100, 5, 2}, 10, false},
+		{"divide four", constants.OpDivision, []float64{120, 2, 3, 4}, 5, false},
+		{"zero in a later divisor", constants.OpDivision, []float64{100, 5, 0}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(tt.operation, tt.operands)
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrDivisionByZero) {
+					t.Errorf("%s: expected ErrDivisionByZero, got %v", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestCalculateAverage tests the mean of a variable number of operands.
+func TestCalculateAverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands []float64
+		expected float64
+		hasError bool
+	}{
+		{"three operands", []float64{2, 4, 6}, 4, false},
+		{"single operand", []float64{7.5}, 7.5, false},
+		{"fractional mean", []float64{1, 2}, 1.5, false},
+		{"negatives", []float64{-3, 3, -6}, -2, false},
+		{"empty", []float64{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(constants.OpAverage, tt.operands)
+			if tt.hasError {
+				var validationErr *errors.ValidationError
+				if !stderrors.As(err, &validationErr) {
+					t.Errorf("%s: expected ValidationError, got %v", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestCalculateMultiplication tests the multiplication operation.
 func TestCalculateMultiplication(t *testing.T) {
 	result, err := Calculate(constants.OpMultiplication, []float64{4, 5})
@@ -73,6 +150,46 @@ func TestCalculatePower(t *testing.T) {
 	}
 }
 
+// TestPowerOverflow tests that results too large to represent are rejected.
+func TestPowerOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     float64
+		exponent float64
+		expected float64
+		hasError bool
+	}{
+		{"normal", 10, 3, 1000, false},
+		{"at the limit", 10, 15, 1e15, false},
+		{"above the limit", 10, 16, 0, true},
+		{"overflows to infinity", 10, 400, 0, true},
+		{"negative overflow", -10, 401, 0, true},
+		{"tiny result", 10, -400, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := power(tt.base, tt.exponent)
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrOutOfRange) {
+					t.Errorf("%s: expected ErrOutOfRange, got %v", tt.name, err)
+				}
+				var calcErr *errors.CalculationError
+				if !stderrors.As(err, &calcErr) {
+					t.Errorf("%s: expected a CalculationError, got %T", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestCalculateSquareRoot tests the square root operation.
 func TestCalculateSquareRoot(t *testing.T) {
 	result, err := Calculate(constants.OpSquareRoot, []float64{16})
@@ -103,6 +220,96 @@ func TestCalculateModulo(t *testing.T) {
 	}
 }
 
+// TestModuloModes tests truncated and Euclidean remainders, especially
+// for negative operands.
+func TestModuloModes(t *testing.T) {
+	tests := []struct {
+		a, b      float64
+		truncated float64
+		euclidean float64
+	}{
+		{10, 3, 1, 1},
+		{-1, 3, -1, 2},
+		{-7, 3, -1, 2},
+		{7, -3, 1, 1},
+		{-7, -3, -1, 2},
+		{-6, 3, 0, 0},
+		{-5.5, 2, -1.5, 0.5},
+	}
+
+	for _, tt := range tests {
+		for mode, expected := range map[string]float64{
+			constants.ModuloTruncated: tt.truncated,
+			constants.ModuloEuclidean: tt.euclidean,
+		} {
+			opts := DefaultOptions()
+			opts.ModuloMode = mode
+			result, err := CalculateWithOptions(constants.OpModulo, []float64{tt.a, tt.b}, opts)
+			if err != nil {
+				t.Errorf("%g mod %g (%s): unexpected error: %v", tt.a, tt.b, mode, err)
+				continue
+			}
+			if result.Value != expected {
+				t.Errorf("%g mod %g (%s): expected %g, got %g", tt.a, tt.b, mode, expected, result.Value)
+			}
+		}
+	}
+}
+
+// TestEuclideanModuloTinyNegative tests that rounding never yields a
+// remainder equal to the divisor.
+func TestEuclideanModuloTinyNegative(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ModuloMode = constants.ModuloEuclidean
+	result, err := CalculateWithOptions(constants.OpModulo, []float64{-1e-20, 3}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Value < 0 || result.Value >= 3 {
+		t.Errorf("Expected remainder in [0, 3), got %g", result.Value)
+	}
+}
+
+// TestDivMod tests the whole-number quotient and remainder.
+func TestDivMod(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      float64
+		quotient  float64
+		remainder float64
+		hasError  bool
+	}{
+		{"17 / 5", 17, 5, 3, 2, false},
+		{"exact", 20, 5, 4, 0, false},
+		{"smaller dividend", 3, 5, 0, 3, false},
+		{"negative dividend", -17, 5, -3, -2, false},
+		{"negative divisor", 17, -5, -3, 2, false},
+		{"fractions", 7.5, 2, 3, 1.5, false},
+		{"divide by zero", 17, 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotient, remainder, err := DivMod(tt.a, tt.b)
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrDivisionByZero) {
+					t.Errorf("%s: expected ErrDivisionByZero, got %v", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if quotient != tt.quotient || remainder != tt.remainder {
+				t.Errorf("%s: expected %g remainder %g, got %g remainder %g", tt.name, tt.quotient, tt.remainder, quotient, remainder)
+			}
+			if quotient*tt.b+remainder != tt.a {
+				t.Errorf("%s: expected quotient*b + remainder == %g, got %g", tt.name, tt.a, quotient*tt.b+remainder)
+			}
+		})
+	}
+}
+
 // TestCalculateFactorial tests the factorial operation.
 // This demonstrates table-driven tests.
 func TestCalculateFactorial(t *testing.T) {
@@ -140,6 +347,250 @@ func TestCalculateFactorial(t *testing.T) {
 	}
 }
 
+// TestRoundTo tests rounding to a number of decimal places.
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     float64
+		precision int
+		expected  float64
+	}{
+		{"float noise", 0.1 + 0.2, 2, 0.3},
+		{"round up", 2.675001, 2, 2.68},
+		{"round down", 3.14159, 3, 3.142},
+		{"zero precision", 7.5, 0, 8},
+		{"negative", -1.005001, 2, -1.01},
+		{"already exact", 42, 2, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RoundTo(tt.input, tt.precision)
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+
+	if !math.IsInf(RoundTo(math.Inf(1), 2), 1) {
+		t.Error("Expected +Inf to be returned unchanged")
+	}
+}
+
+// TestCalculateWithMinOperand tests that a floor of 0 rejects negative typed
+// numbers but not negative intermediate results.
+func TestCalculateWithMinOperand(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MinOperand = 0
+
+	tests := []struct {
+		name     string
+		expr     string
+		rpn      string
+		expected float64
+		hasError bool
+	}{
+		{"positive accepted", "5 + 1", "5 1 +", 6, false},
+		{"zero accepted", "0 + 1", "0 1 +", 1, false},
+		{"negative rejected", "-5 + 1", "-5 1 +", 0, true},
+		{"negative second operand rejected", "5 + -1", "5 -1 +", 0, true},
+		{"lone negative rejected", "-5", "-5", 0, true},
+		{"negative intermediate accepted", "3 - 5 + 4", "3 5 - 4 +", 2, false},
+		{"negative intermediate as base", "(2 - 5) ^ 2", "2 5 - 2 ^", 9, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := EvaluateResult(tt.expr, opts)
+			if tt.hasError && err == nil {
+				t.Errorf("%s: expected error for %q, got nil", tt.name, tt.expr)
+			}
+			if !tt.hasError && (err != nil || result.Value != tt.expected) {
+				t.Errorf("%s: expected %g for %q, got %g (%v)", tt.name, tt.expected, tt.expr, result.Value, err)
+			}
+
+			rpnResult, err := EvaluateRPNResult(strings.Fields(tt.rpn), opts)
+			if tt.hasError && err == nil {
+				t.Errorf("%s: expected error for RPN %q, got nil", tt.name, tt.rpn)
+			}
+			if !tt.hasError && (err != nil || rpnResult.Value != tt.expected) {
+				t.Errorf("%s: expected %g for RPN %q, got %g (%v)", tt.name, tt.expected, tt.rpn, rpnResult.Value, err)
+			}
+		})
+	}
+
+	// Operands passed directly may be running results, so only the range applies
+	if _, err := CalculateWithOptions(constants.OpAddition, []float64{-5, 1}, opts); err != nil {
+		t.Errorf("Expected a negative running value to be accepted, got %v", err)
+	}
+}
+
+// TestFactorialConfigurableLimit tests a lowered factorial ceiling and the default.
+func TestFactorialConfigurableLimit(t *testing.T) {
+	lowered := DefaultOptions()
+	lowered.MaxFactorialInput = 10
+
+	tests := []struct {
+		name     string
+		opts     Options
+		input    float64
+		hasError bool
+	}{
+		{"at lowered limit", lowered, 10, false},
+		{"above lowered limit", lowered, 11, true},
+		{"default allows 170", DefaultOptions(), 170, false},
+		{"default rejects 171", DefaultOptions(), 171, true},
+		{"unset uses default", Options{MaxOperand: constants.MaxNumberInputValue, MinOperand: constants.MinNumberInputValue}, 170, false},
+		{"unset still rejects 171", Options{MaxOperand: constants.MaxNumberInputValue, MinOperand: constants.MinNumberInputValue}, 171, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CalculateWithOptions(constants.OpFactorial, []float64{tt.input}, tt.opts)
+			if tt.hasError && err == nil {
+				t.Errorf("%s: expected error, got nil", tt.name)
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// TestCalculateRound tests rounding to a number of decimal places, with
+// halves rounded away from zero.
+func TestCalculateRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		decimals float64
+		expected float64
+		hasError bool
+	}{
+		{"two places", 3.14159, 2, 3.14, false},
+		{"half rounds up", 2.5, 0, 3, false},
+		{"negative half rounds away from zero", -2.5, 0, -3, false},
+		{"decimal half", 1.005, 2, 1.01, false},
+		{"round up carries", 9.999, 2, 10, false},
+		{"already rounded", 1.5, 3, 1.5, false},
+		{"small negative becomes zero", -0.4, 0, 0, false},
+		{"large value", 123456789.987, 1, 123456790, false},
+		{"negative decimals", 3.14159, -1, 0, true},
+		{"non-integer decimals", 3.14159, 1.5, 0, true},
+		{"too many decimals", 3.14159, 16, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(constants.OpRound, []float64{tt.value, tt.decimals})
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrInvalidInput) {
+					t.Errorf("%s: expected ErrInvalidInput, got %v (result %v)", tt.name, err, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected || math.Signbit(result) != math.Signbit(tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestCalculatePercentChange tests the percent change operation.
+func TestCalculatePercentChange(t *testing.T) {
+	result, err := Calculate(constants.OpPercentChange, []float64{200, 250})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if result != 25 {
+		t.Errorf("Expected 25, got %f", result)
+	}
+}
+
+// TestCalculatePercentChangeFromZero tests that a zero starting value is rejected.
+func TestCalculatePercentChangeFromZero(t *testing.T) {
+	_, err := Calculate(constants.OpPercentChange, []float64{0, 50})
+	if err == nil {
+		t.Error("Expected error for percent change from zero, got nil")
+	}
+}
+
+// TestRatio tests reducing ratios to lowest terms.
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     float64
+		expected string
+		hasError bool
+	}{
+		{"reducible", 4, 6, "2:3", false},
+		{"already lowest", 3, 7, "3:7", false},
+		{"equal terms", 5, 5, "1:1", false},
+		{"decimal terms", 1.5, 2, "3:4", false},
+		{"negative second term", 4, -6, "-2:3", false},
+		{"zero first term", 0, 6, "0:1", false},
+		{"zero second term", 4, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Ratio(tt.a, tt.b)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %q", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestCompoundInterest tests final amounts and rejected inputs.
+func TestCompoundInterest(t *testing.T) {
+	tests := []struct {
+		name       string
+		p, r, n, y float64
+		expected   float64
+		hasError   bool
+	}{
+		{"monthly for ten years", 1000, 0.05, 12, 10, 1647.00949769028, false},
+		{"yearly", 100, 0.1, 1, 2, 121, false},
+		{"zero rate", 500, 0, 4, 3, 500, false},
+		{"zero years", 500, 0.05, 4, 0, 500, false},
+		{"negative principal", -1000, 0.05, 12, 10, 0, true},
+		{"negative rate", 1000, -0.05, 12, 10, 0, true},
+		{"negative years", 1000, 0.05, 12, -1, 0, true},
+		{"zero periods", 1000, 0.05, 0, 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CompoundInterest(tt.p, tt.r, tt.n, tt.y)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %v", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if !AlmostEqual(result, tt.expected, 1e-9) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestFormatResult tests the result formatting function.
 func TestFormatResult(t *testing.T) {
 	tests := []struct {
@@ -168,6 +619,105 @@ func TestFormatResult(t *testing.T) {
 	}
 }
 
+// TestFormatResultTrimTrailingZeros tests dropping zeros left over after rounding.
+func TestFormatResultTrimTrailingZeros(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     float64
+		precision int
+		separator string
+		expected  string
+	}{
+		{"integer", 8, 2, "", "8"},
+		{"large integer", 1200, 2, "", "1200"},
+		{"some zeros", 3.140, 3, "", "3.14"},
+		{"rounded to integer", 2.999, 2, "", "3"},
+		{"no trailing zeros", 3.14159, 4, "", "3.1416"},
+		{"zero precision", 40, 0, "", "40"},
+		{"zero", 0, 2, "", "0"},
+		{"negative zero", -0.001, 2, "", "0"},
+		{"negative", -7.50, 2, "", "-7.5"},
+		{"comma separator", 2.50, 2, ",", "2,5"},
+		{"NaN", math.NaN(), 2, "", "NaN"},
+		{"positive infinity", math.Inf(1), 2, "", "+Inf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatResultWith(tt.value, FormatOptions{
+				Precision:         tt.precision,
+				DecimalSeparator:  tt.separator,
+				TrimTrailingZeros: true,
+			})
+			if result != tt.expected {
+				t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestFormatResultWholeNumber tests that exact results print without
+// decimals while other results keep the precision.
+func TestFormatResultWholeNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		operands  []float64
+		expected  string
+	}{
+		{"4 * 5", constants.OpMultiplication, []float64{4, 5}, "20"},
+		{"10 / 3", constants.OpDivision, []float64{10, 3}, "3.33"},
+		{"10 / 4", constants.OpDivision, []float64{10, 4}, "2.50"},
+		{"whole result from fractions", constants.OpAddition, []float64{1.5, 1.5}, "3.00"},
+		{"negative", constants.OpSubtraction, []float64{2, 9}, "-7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateResult(tt.operation, tt.operands)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			formatted := FormatResultWith(result.Value, FormatOptions{Precision: 2, WholeNumber: result.Exact})
+			if formatted != tt.expected {
+				t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, formatted)
+			}
+		})
+	}
+
+	// WholeNumber never hides decimals the value actually has
+	if got := FormatResultWith(2.5, FormatOptions{Precision: 2, WholeNumber: true}); got != "2.50" {
+		t.Errorf("Expected '2.50' for a fractional value, got '%s'", got)
+	}
+}
+
+// TestFormatResultWithSeparator tests formatting with a locale decimal separator.
+func TestFormatResultWithSeparator(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		opts     FormatOptions
+		expected string
+	}{
+		{"comma separator", 3.14, FormatOptions{Precision: 2, DecimalSeparator: ","}, "3,14"},
+		{"comma negative", -2.5, FormatOptions{Precision: 1, DecimalSeparator: ","}, "-2,5"},
+		{"comma no decimals", 42, FormatOptions{Precision: 0, DecimalSeparator: ","}, "42"},
+		{"dot separator", 3.14, FormatOptions{Precision: 2, DecimalSeparator: "."}, "3.14"},
+		{"default separator", 3.14, FormatOptions{Precision: 2}, "3.14"},
+		{"NaN unchanged", math.NaN(), FormatOptions{Precision: 2, DecimalSeparator: ","}, "NaN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatResultWith(tt.value, tt.opts)
+			if result != tt.expected {
+				t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestCalculateInvalidOperation tests handling of invalid operations.
 func TestCalculateInvalidOperation(t *testing.T) {
 	_, err := Calculate(constants.Operation(99), []float64{1, 2})
@@ -184,6 +734,166 @@ func TestCalculateEmptyOperands(t *testing.T) {
 	}
 }
 
+// TestAlmostEqual tests tolerance-based float comparison.
+func TestAlmostEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        float64
+		b        float64
+		epsilon  float64
+		expected bool
+	}{
+		{"exactly equal", 1.5, 1.5, 0, true},
+		{"float noise", 0.1 + 0.2, 0.3, 1e-9, true},
+		{"difference at epsilon", 1.0, 1.5, 0.5, true},
+		{"difference above epsilon", 1.0, 1.5000001, 0.5, false},
+		{"negative values", -2.0000001, -2, 1e-6, true},
+		{"zero epsilon differs", 1.0, 1.0000001, 0, false},
+		{"both positive infinity", math.Inf(1), math.Inf(1), 1e-9, true},
+		{"opposite infinities", math.Inf(1), math.Inf(-1), 1e-9, false},
+		{"NaN never equal", math.NaN(), math.NaN(), 1e-9, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AlmostEqual(tt.a, tt.b, tt.epsilon)
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestCalculateResult tests that the structured result is fully populated.
+func TestCalculateResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		operands  []float64
+		value     float64
+		exact     bool
+	}{
+		{"integer multiplication", constants.OpMultiplication, []float64{4, 5}, 20, true},
+		{"non-terminating division", constants.OpDivision, []float64{10, 3}, 10.0 / 3.0, false},
+		{"decimal operands", constants.OpAddition, []float64{0.5, 0.5}, 1, false},
+		{"integer factorial", constants.OpFactorial, []float64{5}, 120, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateResult(tt.operation, tt.operands)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result.Value != tt.value {
+				t.Errorf("%s: expected value %f, got %f", tt.name, tt.value, result.Value)
+			}
+			if result.Operation != tt.operation {
+				t.Errorf("%s: expected operation %v, got %v", tt.name, tt.operation, result.Operation)
+			}
+			if len(result.Operands) != len(tt.operands) {
+				t.Fatalf("%s: expected %d operands, got %d", tt.name, len(tt.operands), len(result.Operands))
+			}
+			for i := range tt.operands {
+				if result.Operands[i] != tt.operands[i] {
+					t.Errorf("%s: operand %d: expected %f, got %f", tt.name, i, tt.operands[i], result.Operands[i])
+				}
+			}
+			if result.Exact != tt.exact {
+				t.Errorf("%s: expected Exact %v, got %v", tt.name, tt.exact, result.Exact)
+			}
+		})
+	}
+}
+
+// TestCalculateResultCopiesOperands tests that the result doesn't alias the input slice.
+func TestCalculateResultCopiesOperands(t *testing.T) {
+	operands := []float64{2, 3}
+	result, err := CalculateResult(constants.OpAddition, operands)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	operands[0] = 100
+	if result.Operands[0] != 2 {
+		t.Errorf("Expected stored operand 2, got %f", result.Operands[0])
+	}
+}
+
+// TestCalculateResultError tests that errors return an empty result.
+func TestCalculateResultError(t *testing.T) {
+	result, err := CalculateResult(constants.OpDivision, []float64{1, 0})
+	if err == nil {
+		t.Fatal("Expected error for division by zero, got nil")
+	}
+	if result.Operation != constants.OpUnknown || result.Operands != nil {
+		t.Errorf("Expected empty result on error, got %+v", result)
+	}
+}
+
+// TestCalculateWithLoweredMaxOperand tests a configured cap tighter than the global one.
+func TestCalculateWithLoweredMaxOperand(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxOperand = 1000
+
+	tests := []struct {
+		name     string
+		operands []float64
+		hasError bool
+	}{
+		{"within cap", []float64{1000, -1000}, false},
+		{"above cap", []float64{5000, 1}, true},
+		{"below negative cap", []float64{1, -5000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The global cap accepts every operand used here
+			if _, err := Calculate(constants.OpAddition, tt.operands); err != nil {
+				t.Fatalf("%s: global cap rejected operands: %v", tt.name, err)
+			}
+
+			_, err := CalculateWithOptions(constants.OpAddition, tt.operands, opts)
+			if tt.hasError && err == nil {
+				t.Errorf("%s: expected error, got nil", tt.name)
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// TestFormatResultAllPrecisions tests that cached formats match building the verb each time.
+func TestFormatResultAllPrecisions(t *testing.T) {
+	values := []float64{0, 1, -1, 3.14159265358979, 2.5, -0.005, 1e15, 123456.789}
+
+	for precision := 0; precision <= 15; precision++ {
+		for _, value := range values {
+			expected := fmt.Sprintf(fmt.Sprintf("%%.%df", precision), value)
+			if got := FormatResult(value, precision); got != expected {
+				t.Errorf("precision %d, value %v: expected %q, got %q", precision, value, expected, got)
+			}
+		}
+	}
+}
+
+// BenchmarkFormatResult benchmarks formatting with the cached format verbs.
+func BenchmarkFormatResult(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FormatResult(3.14159265358979, i%16)
+	}
+}
+
+// BenchmarkFormatResultUncached benchmarks the previous approach of building
+// the format verb on every call, for comparison with BenchmarkFormatResult.
+func BenchmarkFormatResultUncached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		format := fmt.Sprintf("%%.%df", i%16)
+		_ = fmt.Sprintf(format, 3.14159265358979)
+	}
+}
+
 // BenchmarkCalculateAddition benchmarks the addition operation.
 // This demonstrates benchmark functions in Go.
 func BenchmarkCalculateAddition(b *testing.B) {
@@ -0,0 +1,343 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind identifies the type of a lexical token in an expression.
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+// token is a single lexical element of an infix expression.
+type token struct {
+	kind  tokenKind
+	text  string  // Original text (operator symbol or number literal)
+	value float64 // Parsed value for number tokens
+	unary bool    // True for a prefix minus/plus
+}
+
+// binaryOperators maps operator symbols to calculator operations.
+var binaryOperators = map[string]constants.Operation{
+	"+": constants.OpAddition,
+	"-": constants.OpSubtraction,
+	"*": constants.OpMultiplication,
+	"/": constants.OpDivision,
+	"%": constants.OpModulo,
+	"^": constants.OpPower,
+}
+
+// precedence returns the binding strength of an operator token.
+// Higher values bind tighter.
+// A prefix sign binds looser than ^ so that -2^2 = -(2^2), as in mathematics.
+func precedence(t token) int {
+	if t.unary {
+		return 3
+	}
+	switch t.text {
+	case "^":
+		return 4
+	case "*", "/", "%":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// rightAssociative reports whether an operator groups from the right (2^3^2 = 2^9).
+func rightAssociative(t token) bool {
+	return t.unary || t.text == "^"
+}
+
+// Evaluate evaluates an infix expression such as "2 + 3 * (4 - 1)".
+// Supported operators are + - * / % ^ with the usual precedence and parentheses.
+func Evaluate(expr string) (float64, error) {
+	result, _, err := EvaluateVerbose(expr)
+	return result, err
+}
+
+// EvaluateVerbose evaluates an infix expression and also returns each reduction
+// step in evaluation order (e.g., "3 * 4 = 12", "2 + 12 = 14").
+// This demonstrates a classic two-stage evaluator: the shunting-yard algorithm
+// converts infix to postfix, and a stack then reduces the postfix tokens.
+func EvaluateVerbose(expr string) (float64, []string, error) {
+	return EvaluateVerboseWithOptions(expr, DefaultOptions())
+}
+
+// EvaluateVerboseWithOptions is EvaluateVerbose with configurable limits.
+func EvaluateVerboseWithOptions(expr string, opts Options) (float64, []string, error) {
+	result, steps, err := EvaluateResult(expr, opts)
+	return result.Value, steps, err
+}
+
+// EvaluateResult is EvaluateVerboseWithOptions returning a Result, whose
+// Exact field is set when every number and every step was a whole number,
+// so 4 * 5 can be shown as 20 rather than 20.00. The Operation is OpUnknown
+// since an expression may mix several.
+func EvaluateResult(expr string, opts Options) (Result, []string, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	postfix, err := toPostfix(tokens)
+	if err != nil {
+		return Result{}, nil, err
+	}
+
+	return evaluatePostfix(postfix, opts)
+}
+
+// tokenize splits an expression into number, operator, and parenthesis tokens.
+func tokenize(expr string) ([]token, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, errors.NewValidationError("expression", expr, "cannot be empty")
+	}
+
+	tokens := make([]token, 0, len(expr))
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")"})
+			i++
+		case strings.ContainsRune("+-*/%^", r):
+			tokens = appendOperator(tokens, string(r))
+			i++
+		case unicode.IsLetter(r):
+			// Words such as "plus" or "times" resolve through the alias table
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			op, err := ResolveOperation(word)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := binaryOperators[op.Symbol()]; !ok {
+				return nil, errors.NewValidationError("expression", word, fmt.Sprintf("%s cannot be used between two numbers", op.String()))
+			}
+			tokens = appendOperator(tokens, op.Symbol())
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// An exponent (1e5, 2.5E-3) belongs to the number, so it is
+			// read here before "e" could be taken for a word
+			i = exponentEnd(runes, i)
+			literal := string(runes[start:i])
+			value, err := strconv.ParseFloat(literal, 64)
+			if err != nil {
+				return nil, errors.NewValidationError("expression", literal, "not a valid number")
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: literal, value: value})
+		default:
+			return nil, errors.NewValidationError("expression", string(r), "unexpected character")
+		}
+	}
+
+	return tokens, nil
+}
+
+// exponentEnd returns the index just past an exponent such as "e5" or "E-3"
+// starting at i, or i itself when no digits follow the "e".
+func exponentEnd(runes []rune, i int) int {
+	if i >= len(runes) || (runes[i] != 'e' && runes[i] != 'E') {
+		return i
+	}
+	j := i + 1
+	if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+		j++
+	}
+	if j >= len(runes) || !unicode.IsDigit(runes[j]) {
+		return i
+	}
+	for j < len(runes) && unicode.IsDigit(runes[j]) {
+		j++
+	}
+	return j
+}
+
+// appendOperator adds an operator token, deciding whether a sign is unary.
+// A sign is unary at the start, after another operator, or after "(".
+func appendOperator(tokens []token, symbol string) []token {
+	unary := false
+	if symbol == "-" || symbol == "+" {
+		if len(tokens) == 0 {
+			unary = true
+		} else {
+			prev := tokens[len(tokens)-1]
+			unary = prev.kind == tokenOperator || prev.kind == tokenLeftParen
+		}
+	}
+	return append(tokens, token{kind: tokenOperator, text: symbol, unary: unary})
+}
+
+// toPostfix converts infix tokens to postfix order using the shunting-yard algorithm.
+func toPostfix(tokens []token) ([]token, error) {
+	output := make([]token, 0, len(tokens))
+	stack := make([]token, 0)
+
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenNumber:
+			output = append(output, t)
+		case tokenOperator:
+			// Pop operators that bind at least as tightly (prefix operators never pop)
+			for !t.unary && len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.kind != tokenOperator {
+					break
+				}
+				if precedence(top) > precedence(t) || (precedence(top) == precedence(t) && !rightAssociative(t)) {
+					output = append(output, top)
+					stack = stack[:len(stack)-1]
+					continue
+				}
+				break
+			}
+			stack = append(stack, t)
+		case tokenLeftParen:
+			stack = append(stack, t)
+		case tokenRightParen:
+			matched := false
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if top.kind == tokenLeftParen {
+					matched = true
+					break
+				}
+				output = append(output, top)
+			}
+			if !matched {
+				return nil, errors.NewCalculationError("Expression", nil, "')' has no matching '('", errors.ErrUnbalancedParens)
+			}
+		}
+	}
+
+	// Drain remaining operators; any "(" left here was never closed
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if top.kind == tokenLeftParen {
+			return nil, errors.NewCalculationError("Expression", nil, "'(' is never closed", errors.ErrUnbalancedParens)
+		}
+		output = append(output, top)
+	}
+
+	return output, nil
+}
+
+// evaluatePostfix reduces postfix tokens with a value stack, recording each
+// step and whether all of them were exact. The MinOperand floor applies to
+// the numbers as typed (with their sign), not to intermediate results.
+func evaluatePostfix(postfix []token, opts Options) (Result, []string, error) {
+	stack := make([]float64, 0, len(postfix))
+	typed := make([]bool, 0, len(postfix)) // whether each stack value is a typed number
+	steps := make([]string, 0)
+	exact := true
+
+	for _, t := range postfix {
+		if t.kind == tokenNumber {
+			stack = append(stack, t.value)
+			typed = append(typed, true)
+			exact = exact && isExactInteger(t.value)
+			continue
+		}
+
+		// Prefix sign applies to a single value
+		if t.unary {
+			if len(stack) < 1 {
+				return Result{}, steps, malformedExpression(t.text)
+			}
+			if t.text == "-" {
+				stack[len(stack)-1] = -stack[len(stack)-1]
+			}
+			continue
+		}
+
+		if len(stack) < 2 {
+			return Result{}, steps, malformedExpression(t.text)
+		}
+		a, b := stack[len(stack)-2], stack[len(stack)-1]
+		for i, value := range []float64{a, b} {
+			if typed[len(typed)-2+i] {
+				if err := validateMinOperand([]float64{value}, opts); err != nil {
+					return Result{}, steps, err
+				}
+			}
+		}
+		stack = stack[:len(stack)-2]
+		typed = typed[:len(typed)-2]
+
+		calc, err := CalculateWithOptions(binaryOperators[t.text], []float64{a, b}, opts)
+		if err != nil {
+			return Result{}, steps, err
+		}
+		result := calc.Value
+		exact = exact && calc.Exact
+
+		// math.Pow can overflow or leave the reals without an error
+		// (9^999, (-8)^0.5); stop before the bad value feeds later steps
+		if math.IsInf(result, 0) || math.IsNaN(result) {
+			return Result{}, steps, errors.NewCalculationError(
+				"Expression",
+				[]float64{a, b},
+				fmt.Sprintf("%s %s %s is not a finite number", formatNumber(a), t.text, formatNumber(b)),
+				errors.ErrInvalidInput,
+			)
+		}
+
+		steps = append(steps, fmt.Sprintf("%s %s %s = %s", formatNumber(a), t.text, formatNumber(b), formatNumber(result)))
+		stack = append(stack, result)
+		typed = append(typed, false)
+	}
+
+	if len(stack) != 1 {
+		return Result{}, steps, malformedExpression("")
+	}
+	// A lone number ("-3") never reaches an operator
+	if typed[0] {
+		if err := validateMinOperand(stack, opts); err != nil {
+			return Result{}, steps, err
+		}
+	}
+
+	return Result{Value: stack[0], Exact: exact}, steps, nil
+}
+
+// malformedExpression returns the error used when operators and operands don't line up.
+func malformedExpression(near string) error {
+	reason := "malformed expression"
+	if near != "" {
+		reason = fmt.Sprintf("missing operand for '%s'", near)
+	}
+	return errors.NewCalculationError("Expression", nil, reason, errors.ErrInvalidInput)
+}
+
+// formatNumber renders a value in its shortest exact form for step traces.
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
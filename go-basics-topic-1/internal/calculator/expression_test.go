@@ -0,0 +1,185 @@
+// Package calculator provides expression evaluation with tests.
+package calculator
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"math"
+	"testing"
+)
+
+// TestEvaluate tests infix expression evaluation.
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected float64
+		hasError bool
+	}{
+		{"single number", "42", 42, false},
+		{"precedence", "2 + 3 * 4", 14, false},
+		{"parentheses", "(2 + 3) * 4", 20, false},
+		{"left associative subtraction", "10 - 3 - 2", 5, false},
+		{"right associative power", "2 ^ 3 ^ 2", 512, false},
+		{"unary minus", "-3 + 5", 2, false},
+		{"negated power", "-2 ^ 2", -4, false},
+		{"negative exponent", "2 ^ -1", 0.5, false},
+		{"modulo", "10 % 4", 2, false},
+		{"decimals", "0.5 * 4", 2, false},
+		{"exponent notation", "1e5 + 1", 100001, false},
+		{"signed exponent", "2.5E-3 * 1000", 2.5, false},
+		{"exponent before word", "1e3 times 2", 2000, false},
+		{"exponent too large", "1e400", 0, true},
+		{"exponent without digits", "2e + 1", 0, true},
+		{"nested parentheses", "((1 + 2) * (3 + 4)) / 7", 3, false},
+		{"empty", "", 0, true},
+		{"unexpected character", "2 & 3", 0, true},
+		{"missing operand", "2 +", 0, true},
+		{"unclosed parenthesis", "(2 + 3", 0, true},
+		{"unopened parenthesis", "2 + 3)", 0, true},
+		{"division by zero", "1 / (2 - 2)", 0, true},
+		{"adjacent numbers", "2 3", 0, true},
+		{"word operators", "2 plus 3 times 4", 14, false},
+		{"mixed case words", "10 MINUS 4 Over 2", 8, false},
+		{"unknown word", "2 plux 3", 0, true},
+		{"unary-only operation word", "2 sqrt 3", 0, true},
+		{"power overflow", "9 ^ 999", 0, true},
+		{"complex power", "(0 - 8) ^ 0.5", 0, true},
+		{"zero to negative power", "0 ^ -1", 0, true},
+		{"empty parentheses", "()", 0, true},
+		{"operator only", "*", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Evaluate(tt.expr)
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				if result != tt.expected {
+					t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestEvaluateVerboseSteps tests that reduction steps follow operator precedence.
+func TestEvaluateVerboseSteps(t *testing.T) {
+	result, steps, err := EvaluateVerbose("2 + 3 * 4")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 14 {
+		t.Errorf("Expected 14, got %f", result)
+	}
+
+	expected := []string{"3 * 4 = 12", "2 + 12 = 14"}
+	if len(steps) != len(expected) {
+		t.Fatalf("Expected %d steps, got %d: %v", len(expected), len(steps), steps)
+	}
+	for i := range expected {
+		if steps[i] != expected[i] {
+			t.Errorf("Step %d: expected '%s', got '%s'", i, expected[i], steps[i])
+		}
+	}
+}
+
+// TestEvaluateResultExact tests when an expression's result counts as exact.
+func TestEvaluateResultExact(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected float64
+		exact    bool
+	}{
+		{"4 * 5", 20, true},
+		{"-(2 + 3) ^ 2", -25, true},
+		{"7", 7, true},
+		{"10 / 3", 10.0 / 3, false},
+		{"10 / 4 * 2", 5, false}, // Whole result through a fractional step
+		{"0.5 + 0.5", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, _, err := EvaluateResult(tt.expr, DefaultOptions())
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.expr, err)
+			}
+			if result.Value != tt.expected || result.Exact != tt.exact {
+				t.Errorf("%s: expected %v (exact %v), got %v (exact %v)", tt.expr, tt.expected, tt.exact, result.Value, result.Exact)
+			}
+		})
+	}
+}
+
+// TestEvaluateUnbalancedParens tests that mismatched parentheses report
+// ErrUnbalancedParens and that properly nested ones evaluate.
+func TestEvaluateUnbalancedParens(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		unbalanced bool
+	}{
+		{"unclosed", "(2 + 3", true},
+		{"unopened", "2 + 3)", true},
+		{"unclosed nested", "((2 + 3) * 4", true},
+		{"closed before opened", ")2 + 3(", true},
+		{"simple", "(2 + 3)", false},
+		{"nested", "((2 + 3) * (4 - 1))", false},
+		{"deeply nested", "(((1)))", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Evaluate(tt.expr)
+			if !tt.unbalanced {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				return
+			}
+
+			if !stderrors.Is(err, errors.ErrUnbalancedParens) {
+				t.Errorf("%s: expected ErrUnbalancedParens, got %v", tt.name, err)
+			}
+			if !stderrors.Is(err, errors.ErrInvalidInput) {
+				t.Errorf("%s: expected error to also match ErrInvalidInput, got %v", tt.name, err)
+			}
+			var calcErr *errors.CalculationError
+			if !stderrors.As(err, &calcErr) {
+				t.Errorf("%s: expected CalculationError, got %T", tt.name, err)
+			}
+		})
+	}
+}
+
+// FuzzEvaluate checks that Evaluate never panics or hangs on arbitrary input.
+// Any expression it accepts must produce a finite result.
+func FuzzEvaluate(f *testing.F) {
+	seeds := []string{
+		"1", "2 + 3", "2 + 3 * 4", "(2 + 3) * 4", "2 ^ 3 ^ 2", "-2 ^ 2", "10 % 3",
+		"((1 + 2) * (3 - 4)) / 5", "1 / 0", "5 % 0", "(", ")", "(()", "())", "()",
+		"1 +", "* 2", "--1", "+-+1", "1..2", ".", "2 plus 3 times 4", "sqrt",
+		"9 ^ 999", "1e5", "( 1 ) ( 2 )", "√4", "٣ + ٤",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, expr string) {
+		result, steps, err := EvaluateVerbose(expr)
+		if err != nil {
+			return
+		}
+		if math.IsInf(result, 0) || math.IsNaN(result) {
+			t.Fatalf("%q: accepted non-finite result %v (steps %v)", expr, result, steps)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"math"
+)
+
+// maxFractionTerms bounds the continued-fraction expansion; float64 never
+// needs more terms than this to reach its own precision.
+const maxFractionTerms = 64
+
+// ToFraction finds the fraction num/den closest to value whose denominator is
+// at most maxDenominator, e.g. 0.75 gives 3/4 and 0.3333333333 gives 1/3.
+// ok is false when even the best such fraction is further than
+// constants.DefaultEpsilon (relative to value) from it, as for irrational
+// numbers like π. Whole numbers come back with den 1; den is never negative.
+// This demonstrates continued fractions: each convergent is the best rational
+// approximation for its denominator size.
+func ToFraction(value float64, maxDenominator int) (num, den int64, ok bool) {
+	if maxDenominator < 1 || math.IsNaN(value) || math.IsInf(value, 0) || math.Abs(value) > maxExactInteger {
+		return 0, 0, false
+	}
+
+	sign := int64(1)
+	if value < 0 {
+		sign, value = -1, -value
+	}
+	tolerance := constants.DefaultEpsilon * math.Max(1, value)
+
+	// Keep numerators within int64: a numerator is about value times its denominator
+	limit := int64(maxDenominator)
+	if bound := int64(math.MaxInt64 / 2 / (value + 1)); bound < limit {
+		limit = bound
+	}
+
+	// Convergents h/k, seeded with h₋₂/k₋₂ = 0/1 and h₋₁/k₋₁ = 1/0
+	hPrev, h := int64(0), int64(1)
+	kPrev, k := int64(1), int64(0)
+	x := value
+	for i := 0; i < maxFractionTerms; i++ {
+		a := math.Floor(x)
+		term := int64(a)
+		kNext := term*k + kPrev
+		if kNext > limit || kNext < 0 {
+			break
+		}
+		hPrev, h = h, term*h+hPrev
+		kPrev, k = k, kNext
+
+		remainder := x - a
+		if math.Abs(float64(h)/float64(k)-value) <= tolerance || remainder == 0 {
+			break
+		}
+		x = 1 / remainder
+	}
+
+	if k == 0 || math.Abs(float64(h)/float64(k)-value) > tolerance {
+		return 0, 0, false
+	}
+	return sign * h, k, true
+}
@@ -0,0 +1,46 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestToFraction tests finding fractions within a denominator limit.
+func TestToFraction(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          float64
+		maxDenominator int
+		num, den       int64
+		ok             bool
+	}{
+		{"three quarters", 0.75, 1000, 3, 4, true},
+		{"one third", 1.0 / 3, 1000, 1, 3, true},
+		{"one third typed out", 0.3333333333, 1000, 1, 3, true},
+		{"negative", -2.5, 1000, -5, 2, true},
+		{"whole number", 42, 1000, 42, 1, true},
+		{"zero", 0, 1000, 0, 1, true},
+		{"repeating with larger denominator", 22.0 / 7, 1000, 22, 7, true},
+		{"needs a larger denominator", 0.001, 100, 0, 0, false},
+		{"pi is irrational", math.Pi, 1000, 0, 0, false},
+		{"square root of two", math.Sqrt2, 10000, 0, 0, false},
+		{"invalid limit", 0.5, 0, 0, 0, false},
+		{"NaN", math.NaN(), 1000, 0, 0, false},
+		{"infinity", math.Inf(1), 1000, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, den, ok := ToFraction(tt.value, tt.maxDenominator)
+			if ok != tt.ok {
+				t.Fatalf("%s: expected ok %v, got %v (%d/%d)", tt.name, tt.ok, ok, num, den)
+			}
+			if ok && (num != tt.num || den != tt.den) {
+				t.Errorf("%s: expected %d/%d, got %d/%d", tt.name, tt.num, tt.den, num, den)
+			}
+			if ok && den > int64(tt.maxDenominator) {
+				t.Errorf("%s: denominator %d exceeds limit %d", tt.name, den, tt.maxDenominator)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package calculator
+
+import (
+	"cli-calculator/internal/errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// VariadicArity marks a registered operation that accepts one or more operands.
+const VariadicArity = -1
+
+// OperationFunc is the signature of a user-defined operation.
+// This demonstrates function types used as values.
+type OperationFunc func(operands []float64) (float64, error)
+
+// customOperation is a registered operation and its expected operand count.
+type customOperation struct {
+	arity int
+	fn    OperationFunc
+}
+
+// registry holds user-defined operations keyed by lower-case name.
+// The mutex makes registration safe from multiple goroutines.
+var registry = struct {
+	sync.RWMutex
+	operations map[string]customOperation
+}{operations: make(map[string]customOperation)}
+
+// normalizeName makes operation lookups case- and whitespace-insensitive.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Register adds a named operation that CalculateNamed can dispatch to.
+// arity is the exact number of operands required, or VariadicArity for one
+// or more. Registering an existing name replaces it. Like other Go registries
+// (e.g. database/sql.Register), it panics on programmer errors: an empty name,
+// a nil function, or an invalid arity.
+func Register(name string, arity int, fn func([]float64) (float64, error)) {
+	key := normalizeName(name)
+	if key == "" {
+		panic("calculator: Register called with empty name")
+	}
+	if fn == nil {
+		panic("calculator: Register called with nil function for " + key)
+	}
+	if arity == 0 || arity < VariadicArity {
+		panic(fmt.Sprintf("calculator: invalid arity %d for %s", arity, key))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.operations[key] = customOperation{arity: arity, fn: fn}
+}
+
+// Unregister removes a named operation. Unknown names are ignored.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.operations, normalizeName(name))
+}
+
+// CalculateNamed runs a registered operation after checking its arity and
+// validating the operands the same way built-in operations do.
+func CalculateNamed(name string, operands []float64) (float64, error) {
+	key := normalizeName(name)
+
+	registry.RLock()
+	op, ok := registry.operations[key]
+	registry.RUnlock()
+
+	if !ok {
+		return 0, errors.NewCalculationError(
+			name,
+			operands,
+			"no operation registered with this name",
+			errors.ErrInvalidOperation,
+		)
+	}
+
+	// Check operand count against the registered arity
+	if op.arity == VariadicArity && len(operands) == 0 {
+		return 0, errors.NewValidationError("operands", "0", fmt.Sprintf("%s requires at least 1 operand", key))
+	}
+	if op.arity != VariadicArity && len(operands) != op.arity {
+		return 0, errors.NewValidationError(
+			"operands",
+			fmt.Sprintf("%d", len(operands)),
+			fmt.Sprintf("%s requires %d operands, got %d", key, op.arity, len(operands)),
+		)
+	}
+
+	if err := validateOperands(operands, DefaultOptions()); err != nil {
+		return 0, err
+	}
+
+	return op.fn(operands)
+}
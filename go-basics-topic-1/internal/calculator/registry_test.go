@@ -0,0 +1,100 @@
+// Package calculator provides the custom operation registry with tests.
+package calculator
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"testing"
+)
+
+// average is a sample user-defined operation returning the mean of its operands.
+func average(operands []float64) (float64, error) {
+	sum := 0.0
+	for _, val := range operands {
+		sum += val
+	}
+	return sum / float64(len(operands)), nil
+}
+
+// TestCalculateNamedCustomOperation tests registering and invoking a custom operation.
+func TestCalculateNamedCustomOperation(t *testing.T) {
+	Register("avg", VariadicArity, average)
+	defer Unregister("avg")
+
+	result, err := CalculateNamed("avg", []float64{2, 4, 6})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != 4 {
+		t.Errorf("Expected 4, got %f", result)
+	}
+
+	// Names are case-insensitive
+	if _, err := CalculateNamed(" AVG ", []float64{1}); err != nil {
+		t.Errorf("Expected case-insensitive lookup, got error: %v", err)
+	}
+}
+
+// TestCalculateNamedArityMismatch tests that operand counts are enforced.
+func TestCalculateNamedArityMismatch(t *testing.T) {
+	Register("hypot", 2, func(operands []float64) (float64, error) {
+		return operands[0]*operands[0] + operands[1]*operands[1], nil
+	})
+	defer Unregister("hypot")
+
+	_, err := CalculateNamed("hypot", []float64{3})
+	if err == nil {
+		t.Fatal("Expected arity error, got nil")
+	}
+
+	var validationErr *errors.ValidationError
+	if !stderrors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError, got %T", err)
+	}
+}
+
+// TestCalculateNamedUnknown tests calling an operation that was never registered.
+func TestCalculateNamedUnknown(t *testing.T) {
+	_, err := CalculateNamed("nope", []float64{1})
+	if !stderrors.Is(err, errors.ErrInvalidOperation) {
+		t.Errorf("Expected ErrInvalidOperation, got %v", err)
+	}
+}
+
+// TestCalculateNamedValidatesOperands tests that custom operations get the built-in operand checks.
+func TestCalculateNamedValidatesOperands(t *testing.T) {
+	Register("avg", VariadicArity, average)
+	defer Unregister("avg")
+
+	if _, err := CalculateNamed("avg", []float64{1, 2e15}); err == nil {
+		t.Error("Expected out-of-range operand error, got nil")
+	}
+	if _, err := CalculateNamed("avg", nil); err == nil {
+		t.Error("Expected error for missing operands, got nil")
+	}
+}
+
+// TestRegisterPanicsOnInvalidInput tests that programmer errors panic.
+func TestRegisterPanicsOnInvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		opName string
+		arity  int
+		fn     func([]float64) (float64, error)
+	}{
+		{"empty name", "", 1, average},
+		{"nil function", "noop", 1, nil},
+		{"zero arity", "noop", 0, average},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic, got none", tt.name)
+				}
+			}()
+			Register(tt.opName, tt.arity, tt.fn)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"strconv"
+)
+
+// EvaluateRPN evaluates a reverse Polish notation expression given as tokens,
+// e.g. ["5", "3", "+", "2", "*"] = 16. Numbers are pushed onto a stack and
+// each operator pops its operands. Any alias ResolveOperation knows works as
+// an operator, so square roots ("sqrt") and factorials ("!") pop one value.
+// A stack with too few operands, or more than one value left at the end,
+// returns an error wrapping ErrInvalidInput. Numbers are checked against the
+// operand limits as they are pushed, so "inf" or "nan" never get in.
+// This demonstrates that a stack alone is enough to evaluate postfix input.
+func EvaluateRPN(tokens []string) (float64, error) {
+	return EvaluateRPNWithOptions(tokens, DefaultOptions())
+}
+
+// EvaluateRPNWithOptions is EvaluateRPN with configurable limits.
+func EvaluateRPNWithOptions(tokens []string, opts Options) (float64, error) {
+	result, err := EvaluateRPNResult(tokens, opts)
+	return result.Value, err
+}
+
+// EvaluateRPNResult is EvaluateRPNWithOptions returning a Result whose Exact
+// field is set, as in EvaluateResult, when every number and every step was a
+// whole number. The Operation is OpUnknown since a line may mix several.
+func EvaluateRPNResult(tokens []string, opts Options) (Result, error) {
+	if len(tokens) == 0 {
+		return Result{}, errors.NewValidationError("expression", "", "cannot be empty")
+	}
+
+	stack := make([]float64, 0, len(tokens))
+	exact := true
+
+	for _, tok := range tokens {
+		if value, err := strconv.ParseFloat(tok, 64); err == nil {
+			if err := validateOperands([]float64{value}, opts); err != nil {
+				return Result{}, err
+			}
+			if err := validateMinOperand([]float64{value}, opts); err != nil {
+				return Result{}, err
+			}
+			stack = append(stack, value)
+			exact = exact && isExactInteger(value)
+			continue
+		}
+
+		operation, err := ResolveOperation(tok)
+		if err != nil {
+			return Result{}, err
+		}
+
+		// Pop as many operands as the operation needs
+		arity := 2
+		if operation == constants.OpSquareRoot || operation == constants.OpFactorial {
+			arity = 1
+		}
+		if len(stack) < arity {
+			return Result{}, malformedExpression(tok)
+		}
+		operands := make([]float64, arity)
+		copy(operands, stack[len(stack)-arity:])
+		stack = stack[:len(stack)-arity]
+
+		result, err := CalculateWithOptions(operation, operands, opts)
+		if err != nil {
+			return Result{}, err
+		}
+		stack = append(stack, result.Value)
+		exact = exact && result.Exact
+	}
+
+	if len(stack) != 1 {
+		return Result{}, malformedExpression("")
+	}
+
+	return Result{Value: stack[0], Exact: exact}, nil
+}
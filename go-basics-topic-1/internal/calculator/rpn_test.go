@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+// TestEvaluateRPN tests reverse Polish notation evaluation.
+func TestEvaluateRPN(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected float64
+		hasError bool
+	}{
+		{"single number", "42", 42, false},
+		{"addition", "5 3 +", 8, false},
+		{"chained", "5 3 + 2 *", 16, false},
+		{"operand order", "10 4 -", 6, false},
+		{"nested", "2 3 4 * +", 14, false},
+		{"negative number", "-3 5 +", 2, false},
+		{"unary square root", "16 sqrt", 4, false},
+		{"unary factorial", "4 !", 24, false},
+		{"word operators", "6 7 times", 42, false},
+		{"percent change", "200 250 pct", 25, false},
+		{"division by zero", "1 0 /", 0, true},
+		{"unknown operator", "1 2 plux", 0, true},
+		{"empty", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateRPN(strings.Fields(tt.expr))
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %f", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestEvaluateRPNResultExact tests that whole-number lines are marked exact.
+func TestEvaluateRPNResultExact(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected float64
+		exact    bool
+	}{
+		{"whole numbers", "5 3 *", 15, true},
+		{"chained", "5 3 + 2 *", 16, true},
+		{"fractional result", "1 2 /", 0.5, false},
+		{"fractional operand", "0.5 4 *", 2, false},
+		{"irrational step", "2 sqrt 2 sqrt *", 2.0000000000000004, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateRPNResult(strings.Fields(tt.expr), DefaultOptions())
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result.Value != tt.expected || result.Exact != tt.exact {
+				t.Errorf("%s: expected %v (exact %v), got %v (exact %v)", tt.name, tt.expected, tt.exact, result.Value, result.Exact)
+			}
+		})
+	}
+}
+
+// TestEvaluateRPNMalformedStack tests that stack underflow and leftovers are invalid input.
+func TestEvaluateRPNMalformedStack(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"underflow", "5 +"},
+		{"operator first", "+ 5 3"},
+		{"unary underflow", "sqrt"},
+		{"leftover operands", "1 2 3 +"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EvaluateRPN(strings.Fields(tt.expr))
+			if !stderrors.Is(err, errors.ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", tt.name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"fmt"
+)
+
+// SelfTestCase is a sample calculation with its known result.
+type SelfTestCase struct {
+	Operands []float64 // Inputs passed to Calculate
+	Expected float64   // The result a working build must produce
+}
+
+// SelfTestResult reports how one operation fared in the self-test.
+type SelfTestResult struct {
+	Operation constants.Operation // The operation checked
+	Case      SelfTestCase        // The sample calculation used
+	Got       float64             // The value Calculate returned
+	Err       error               // Why the check failed; nil when it passed
+}
+
+// Passed reports whether the operation produced its expected result.
+func (r SelfTestResult) Passed() bool {
+	return r.Err == nil
+}
+
+// selfTestCases holds one sample calculation per operation, with results
+// worked out by hand rather than by this package.
+var selfTestCases = map[constants.Operation]SelfTestCase{
+	constants.OpAddition:       {Operands: []float64{2, 3}, Expected: 5},
+	constants.OpSubtraction:    {Operands: []float64{10, 4}, Expected: 6},
+	constants.OpMultiplication: {Operands: []float64{6, 7}, Expected: 42},
+	constants.OpDivision:       {Operands: []float64{10, 4}, Expected: 2.5},
+	constants.OpPower:          {Operands: []float64{2, 10}, Expected: 1024},
+	constants.OpSquareRoot:     {Operands: []float64{144}, Expected: 12},
+	constants.OpModulo:         {Operands: []float64{17, 5}, Expected: 2},
+	constants.OpFactorial:      {Operands: []float64{5}, Expected: 120},
+	constants.OpPercentChange:  {Operands: []float64{50, 75}, Expected: 50},
+	constants.OpRatio:          {Operands: []float64{3, 4}, Expected: 0.75},
+	constants.OpRound:          {Operands: []float64{3.14159, 2}, Expected: 3.14},
+	constants.OpAverage:        {Operands: []float64{2, 4, 6}, Expected: 4},
+}
+
+// RunSelfTest checks every operation against a known result so users can
+// verify a built binary without the source tree or `go test`.
+func RunSelfTest() []SelfTestResult {
+	return runSelfTest(selfTestCases)
+}
+
+// runSelfTest checks each operation in constants.AllOperations against its
+// case. An operation without a case fails, so new operations can't be
+// skipped silently.
+func runSelfTest(cases map[constants.Operation]SelfTestCase) []SelfTestResult {
+	results := make([]SelfTestResult, 0, len(constants.AllOperations))
+
+	for _, operation := range constants.AllOperations {
+		result := SelfTestResult{Operation: operation}
+
+		tc, ok := cases[operation]
+		if !ok {
+			result.Err = fmt.Errorf("no sample calculation for %s", operation)
+			results = append(results, result)
+			continue
+		}
+		result.Case = tc
+
+		got, err := Calculate(operation, tc.Operands)
+		result.Got = got
+		switch {
+		case err != nil:
+			result.Err = err
+		case !AlmostEqual(got, tc.Expected, constants.DefaultEpsilon):
+			result.Err = fmt.Errorf("expected %g, got %g", tc.Expected, got)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
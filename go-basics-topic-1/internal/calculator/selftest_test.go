@@ -0,0 +1,79 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"strings"
+	"testing"
+)
+
+// TestRunSelfTest tests that every operation passes on a healthy build.
+func TestRunSelfTest(t *testing.T) {
+	results := RunSelfTest()
+
+	if len(results) != len(constants.AllOperations) {
+		t.Fatalf("Expected %d results, got %d", len(constants.AllOperations), len(results))
+	}
+	for _, result := range results {
+		if !result.Passed() {
+			t.Errorf("%s: expected pass, got %v", result.Operation, result.Err)
+		}
+	}
+}
+
+// TestRunSelfTestDetectsFailures tests that a wrong expectation or a missing
+// case is reported as a failure of that operation only.
+func TestRunSelfTestDetectsFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(cases map[constants.Operation]SelfTestCase)
+		failing  constants.Operation
+		mentions string
+	}{
+		{
+			name: "wrong expectation",
+			mutate: func(cases map[constants.Operation]SelfTestCase) {
+				cases[constants.OpAddition] = SelfTestCase{Operands: []float64{2, 2}, Expected: 5}
+			},
+			failing:  constants.OpAddition,
+			mentions: "expected 5, got 4",
+		},
+		{
+			name: "calculation error",
+			mutate: func(cases map[constants.Operation]SelfTestCase) {
+				cases[constants.OpDivision] = SelfTestCase{Operands: []float64{1, 0}, Expected: 0}
+			},
+			failing:  constants.OpDivision,
+			mentions: "zero",
+		},
+		{
+			name: "missing case",
+			mutate: func(cases map[constants.Operation]SelfTestCase) {
+				delete(cases, constants.OpRatio)
+			},
+			failing:  constants.OpRatio,
+			mentions: "no sample calculation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := make(map[constants.Operation]SelfTestCase, len(selfTestCases))
+			for op, tc := range selfTestCases {
+				cases[op] = tc
+			}
+			tt.mutate(cases)
+
+			for _, result := range runSelfTest(cases) {
+				if result.Operation != tt.failing {
+					if !result.Passed() {
+						t.Errorf("%s: expected %s to pass, got %v", tt.name, result.Operation, result.Err)
+					}
+					continue
+				}
+				if result.Passed() || !strings.Contains(result.Err.Error(), tt.mentions) {
+					t.Errorf("%s: expected %s to fail mentioning %q, got %v", tt.name, result.Operation, tt.mentions, result.Err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// MaxSweepPoints caps how many calculations a single Sweep may run.
+const MaxSweepPoints = 1000
+
+// sweepTolerance absorbs float error when deciding whether the last step
+// lands on the end of the range (0.1 steps don't add up exactly).
+const sweepTolerance = 1e-9
+
+// Sweep runs one operation repeatedly while one operand varies from from to
+// to (inclusive) in increments of step, for "what-if" tables. The varying
+// value is inserted into fixed at varyIndex, so Sweep(OpPower, []float64{2},
+// 1, 1, 10, 1) computes 2^1 through 2^10. A negative step counts down.
+// The first failing calculation stops the sweep and returns its error.
+func Sweep(op constants.Operation, fixed []float64, varyIndex int, from, to, step float64) ([]Result, error) {
+	return SweepWithOptions(op, fixed, varyIndex, from, to, step, DefaultOptions())
+}
+
+// SweepWithOptions is Sweep with configurable limits.
+func SweepWithOptions(op constants.Operation, fixed []float64, varyIndex int, from, to, step float64, opts Options) ([]Result, error) {
+	if varyIndex < 0 || varyIndex > len(fixed) {
+		return nil, errors.NewValidationError(
+			"vary_index",
+			strconv.Itoa(varyIndex),
+			fmt.Sprintf("must be between 0 and %d", len(fixed)),
+		)
+	}
+
+	count, err := sweepPoints(from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, count)
+	for i := 0; i < count; i++ {
+		// Multiply rather than accumulate so float error doesn't build up
+		x := from + float64(i)*step
+
+		operands := make([]float64, 0, len(fixed)+1)
+		operands = append(operands, fixed[:varyIndex]...)
+		operands = append(operands, x)
+		operands = append(operands, fixed[varyIndex:]...)
+
+		result, err := CalculateWithOptions(op, operands, opts)
+		if err != nil {
+			return nil, errors.WrapWithContext(err, "sweep stopped at %s", formatNumber(x))
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// sweepPoints returns how many values a sweep from from to to by step visits.
+func sweepPoints(from, to, step float64) (int, error) {
+	for _, v := range []float64{from, to, step} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, errors.NewValidationError("sweep", formatNumber(v), "range values must be finite numbers")
+		}
+	}
+	if step == 0 {
+		return 0, errors.NewValidationError("step", "0", "must not be zero")
+	}
+
+	span := (to - from) / step
+	if span < -sweepTolerance {
+		return 0, errors.NewValidationError("step", formatNumber(step), "moves away from the end of the range")
+	}
+
+	// Check before converting, since a huge span would overflow int
+	if span+1 > MaxSweepPoints+sweepTolerance {
+		return 0, errors.NewValidationError(
+			"step",
+			formatNumber(step),
+			fmt.Sprintf("range has more than %d values", MaxSweepPoints),
+		)
+	}
+	return int(math.Floor(span+sweepTolerance)) + 1, nil
+}
@@ -0,0 +1,57 @@
+package calculator
+
+import (
+	"cli-calculator/internal/constants"
+	"testing"
+)
+
+// TestSweep tests varying one operand across a range.
+func TestSweep(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation constants.Operation
+		fixed     []float64
+		varyIndex int
+		from      float64
+		to        float64
+		step      float64
+		expected  []float64
+		hasError  bool
+	}{
+		{"power of two", constants.OpPower, []float64{2}, 1, 1, 10, 1, []float64{2, 4, 8, 16, 32, 64, 128, 256, 512, 1024}, false},
+		{"varying base", constants.OpPower, []float64{2}, 0, 1, 3, 1, []float64{1, 4, 9}, false},
+		{"counting down", constants.OpMultiplication, []float64{10}, 0, 3, 1, -1, []float64{30, 20, 10}, false},
+		{"fractional step reaches the end", constants.OpAddition, []float64{0}, 0, 0, 0.3, 0.1, []float64{0, 0.1, 0.2, 0.3}, false},
+		{"step past the end", constants.OpAddition, []float64{0}, 0, 0, 1, 0.4, []float64{0, 0.4, 0.8}, false},
+		{"single value", constants.OpSquareRoot, nil, 0, 16, 16, 1, []float64{4}, false},
+		{"zero step", constants.OpPower, []float64{2}, 1, 1, 10, 0, nil, true},
+		{"step away from the end", constants.OpPower, []float64{2}, 1, 1, 10, -1, nil, true},
+		{"too many values", constants.OpAddition, []float64{0}, 0, 0, 1e6, 1, nil, true},
+		{"vary index out of range", constants.OpPower, []float64{2}, 2, 1, 10, 1, nil, true},
+		{"failing calculation", constants.OpDivision, []float64{1}, 1, -1, 1, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Sweep(tt.operation, tt.fixed, tt.varyIndex, tt.from, tt.to, tt.step)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %d results", tt.name, len(results))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			if len(results) != len(tt.expected) {
+				t.Fatalf("%s: expected %d results, got %d", tt.name, len(tt.expected), len(results))
+			}
+			for i, want := range tt.expected {
+				if !AlmostEqual(results[i].Value, want, 1e-9) {
+					t.Errorf("%s: result %d: expected %v, got %v", tt.name, i, want, results[i].Value)
+				}
+			}
+		})
+	}
+}
@@ -5,9 +5,16 @@ package config
 import (
 	"cli-calculator/internal/constants"
 	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/system"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 )
 
 // Config represents the application configuration.
@@ -15,20 +22,49 @@ import (
 type Config struct {
 	// Display settings
 	Precision       int  `json:"precision"`        // Number of decimal places
+	OperationPrecision map[string]int `json:"operation_precision"` // Per-operation decimal places (e.g. {"divide": 6}) overriding precision
 	ShowWelcome     bool `json:"show_welcome"`     // Show welcome message
+	BannerText      string `json:"banner_text"`    // Custom welcome banner text; empty shows the default
 	ClearScreen     bool `json:"clear_screen"`     // Clear screen between operations
 	ColorOutput     bool `json:"color_output"`     // Enable colored output
+	DecimalSeparator string `json:"decimal_separator"` // Decimal separator for input and output ("." or ",")
+	TimeFormat       string `json:"time_format"`       // Go time layout for history timestamps
+	SpokenOutput     bool   `json:"spoken_output"`     // Also spell out integer results in words
+	MaxExpressionDisplay int `json:"max_expression_display"` // Characters of an expression shown in history; 0 shows all
+	RelativeTimestamps bool `json:"relative_timestamps"` // Show history times as "2 minutes ago" instead of time_format
+	TrimTrailingZeros bool `json:"trim_trailing_zeros"` // Print 8 instead of 8.00 and 3.14 instead of 3.140
+	ForceDecimals   bool `json:"force_decimals"`   // Show precision decimals even for whole-number results (20.00 instead of 20)
+	ShowFraction    bool `json:"show_fraction"`    // Also show results as fractions, e.g. 0.75 (3/4)
+	MaxFractionDenominator int `json:"max_fraction_denominator"` // Largest denominator show_fraction uses
+	OutputWidth     int  `json:"output_width"`     // Columns for dividers, boxes, and wrapped help text
 
 	// Behavior settings
 	SaveHistory     bool `json:"save_history"`     // Save calculation history
+	HistoryReadOnly bool `json:"history_read_only"` // Browse history without recording or saving
+	DedupHistory    bool `json:"dedup_history"`     // Collapse repeated identical calculations into one entry
 	MaxHistory      int  `json:"max_history"`      // Maximum history entries
+	MaxHistoryBytes int  `json:"max_history_bytes"` // Largest history file in bytes; oldest entries are dropped to fit, 0 means no limit
 	AutoSave        bool `json:"auto_save"`        // Auto-save config changes
+	AutoSaveInterval int `json:"auto_save_interval"` // Seconds between history saves; 0 saves after every calculation
 	ConfirmExit     bool `json:"confirm_exit"`     // Ask confirmation before exit
+	ConfirmExitUnsavedOnly bool `json:"confirm_exit_unsaved_only"` // With confirm_exit, only ask when history has unsaved changes
+	ExitByDefault   bool `json:"exit_by_default"`   // Pressing Enter at the exit confirmation means yes
+	MaxInputRetries int  `json:"max_input_retries"` // Attempts allowed per number prompt
+	PromptForLabel  bool `json:"prompt_for_label"`  // Ask for an optional label after each calculation
+	ChainResults    bool `json:"chain_results"`    // Offer to keep calculating from each menu result (e.g. "* 2")
+	AuditLogPath    string `json:"audit_log_path"`  // Append-only log of every calculation; empty disables it
+	EchoInput       bool `json:"echo_input"`       // Repeat each input line after its prompt, so piped runs leave a full transcript
 
 	// Advanced settings
 	UseRadians      bool    `json:"use_radians"`      // Use radians for trig (for future)
 	ScientificMode  bool    `json:"scientific_mode"`  // Enable scientific notation
 	ThousandSep     bool    `json:"thousand_sep"`     // Use thousand separator
+	MaxOperand      float64 `json:"max_operand"`      // Largest operand magnitude allowed (safe mode)
+	MinOperand      float64 `json:"min_operand"`      // Smallest operand allowed (e.g. 0 forbids negatives)
+	MaxFactorialInput int   `json:"max_factorial_input"` // Largest n accepted by factorial
+	FactorialConfirmAbove int `json:"factorial_confirm_above"` // Ask before computing factorials of larger n (0 never asks)
+	ModuloMode      string  `json:"modulo_mode"`      // "truncated" (like math.Mod) or "euclidean" (never negative)
+	PinnedOperations []string `json:"pinned_operations"` // Operation names or symbols (e.g. "divide", "^") listed in the Quick menu
 
 	// File paths (using pointers to show optional string fields)
 	ConfigPath  *string `json:"-"` // Path to config file (not saved in JSON)
@@ -38,32 +74,73 @@ type Config struct {
 // DefaultConfig returns a configuration with default values.
 // This demonstrates function returning a pointer to a struct.
 func DefaultConfig() *Config {
-	// Get user's home directory for storing config files
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "." // Fallback to current directory
-	}
-
-	configPath := filepath.Join(homeDir, constants.ConfigFileName)
-	historyPath := filepath.Join(homeDir, constants.HistoryFileName)
+	// Config lives in XDG_CONFIG_HOME and history in XDG_DATA_HOME when set,
+	// otherwise both go in the user's home directory
+	homeDir, homeErr := os.UserHomeDir()
+	configPath := filepath.Join(baseDir("XDG_CONFIG_HOME", homeDir, homeErr), constants.ConfigFileName)
+	historyPath := filepath.Join(baseDir("XDG_DATA_HOME", homeDir, homeErr), constants.HistoryFileName)
 
 	return &Config{
 		Precision:      constants.DefaultPrecision,
+		OperationPrecision: map[string]int{},
 		ShowWelcome:    true,
+		BannerText:     "",
 		ClearScreen:    true,
 		ColorOutput:    false,
+		DecimalSeparator: constants.DefaultDecimalSeparator,
+		TimeFormat:       constants.DefaultTimeFormat,
+		SpokenOutput:     false,
+		MaxExpressionDisplay: constants.DefaultMaxExpressionDisplay,
+		RelativeTimestamps: false,
+		TrimTrailingZeros: false,
+		ForceDecimals:    false,
+		ShowFraction:     false,
+		MaxFractionDenominator: constants.DefaultMaxFractionDenominator,
+		OutputWidth:      constants.DefaultOutputWidth,
 		SaveHistory:    true,
+		HistoryReadOnly: false,
+		DedupHistory:    false,
 		MaxHistory:     constants.MaxHistoryEntries,
+		MaxHistoryBytes: 0,
 		AutoSave:       true,
+		AutoSaveInterval: 0,
 		ConfirmExit:    false,
+		ConfirmExitUnsavedOnly: true,
+		ExitByDefault:  false,
+		MaxInputRetries: constants.DefaultMaxRetries,
+		PromptForLabel:  false,
+		ChainResults:    false,
+		AuditLogPath:    "",
+		EchoInput:       false,
 		UseRadians:     false,
 		ScientificMode: false,
 		ThousandSep:    false,
+		MaxOperand:     constants.MaxNumberInputValue,
+		MinOperand:     constants.MinNumberInputValue,
+		MaxFactorialInput: constants.MaxFactorialInput,
+		FactorialConfirmAbove: constants.DefaultFactorialConfirmAbove,
+		ModuloMode:     constants.ModuloTruncated,
+		PinnedOperations: []string{},
 		ConfigPath:     &configPath,
 		HistoryPath:    &historyPath,
 	}
 }
 
+// baseDir picks the directory for a data file: the XDG variable when it holds
+// an absolute path (relative values are ignored, as the XDG spec requires),
+// then the home directory, and finally the current directory with a warning.
+func baseDir(xdgVar, homeDir string, homeErr error) string {
+	if dir := os.Getenv(xdgVar); dir != "" && filepath.IsAbs(dir) {
+		return dir
+	}
+	if homeErr == nil && homeDir != "" {
+		return homeDir
+	}
+
+	logger.Warn("Home directory unavailable and %s not set; storing files in the current directory", xdgVar)
+	return "."
+}
+
 // Load loads configuration from the config file.
 // If the file doesn't exist, it returns the default configuration.
 // This demonstrates file reading and error handling.
@@ -75,6 +152,21 @@ func Load() (*Config, error) {
 		return config, nil
 	}
 
+	return LoadFrom(*config.ConfigPath)
+}
+
+// LoadFrom loads configuration from the given path instead of the default
+// location. The returned config remembers the path so Save writes back to it.
+// If the file doesn't exist, it returns the default configuration.
+func LoadFrom(path string) (*Config, error) {
+	config := DefaultConfig()
+	config.ConfigPath = &path
+
+	// Reading a directory gives a confusing error, so report it plainly
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil, errors.NewFileError(path, "read", errors.ErrIsDirectory)
+	}
+
 	data, err := os.ReadFile(*config.ConfigPath)
 	if err != nil {
 		// If file doesn't exist, return default config (not an error)
@@ -89,6 +181,11 @@ func Load() (*Config, error) {
 		return nil, errors.WrapWithContext(err, "failed to parse config file")
 	}
 
+	// Reject invalid values (e.g. a broken time layout) from hand-edited files
+	if err := config.Validate(); err != nil {
+		return nil, errors.WrapWithContext(err, "invalid config file %s", path)
+	}
+
 	// Restore paths (they're not saved in JSON)
 	configPath := *config.ConfigPath
 	historyPath := *config.HistoryPath
@@ -111,8 +208,9 @@ func (c *Config) Save() error {
 		return errors.WrapWithContext(err, "failed to marshal config")
 	}
 
-	// Write to file with appropriate permissions (0644 = rw-r--r--)
-	if err := os.WriteFile(*c.ConfigPath, data, 0644); err != nil {
+	// Write atomically with appropriate permissions (0644 = rw-r--r--)
+	// so a crash mid-write never leaves a corrupted config behind
+	if err := system.WriteFileAtomic(*c.ConfigPath, data, 0644); err != nil {
 		return errors.NewFileError(*c.ConfigPath, "write", err)
 	}
 
@@ -124,17 +222,194 @@ func (c *Config) Save() error {
 func (c *Config) Validate() error {
 	// Validate precision
 	if c.Precision < 0 || c.Precision > 15 {
-		return errors.NewValidationError("precision", string(rune(c.Precision)), "must be between 0 and 15")
+		return errors.NewValidationError("precision", strconv.Itoa(c.Precision), "must be between 0 and 15")
+	}
+
+	// Validate per-operation precision, in key order so errors are repeatable
+	names := make([]string, 0, len(c.OperationPrecision))
+	for name := range c.OperationPrecision {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[constants.Operation]string)
+	for _, name := range names {
+		field := fmt.Sprintf("operation_precision[%q]", name)
+		op, ok := constants.LookupOperation(name)
+		if !ok {
+			return errors.NewValidationError(field, name, "unknown operation (use a name like \"divide\" or a symbol like \"/\")")
+		}
+		if other, dup := seen[op]; dup {
+			return errors.NewValidationError(field, name, fmt.Sprintf("same operation as %q; set it once", other))
+		}
+		seen[op] = name
+		if precision := c.OperationPrecision[name]; precision < 0 || precision > 15 {
+			return errors.NewValidationError(field, strconv.Itoa(precision), "must be between 0 and 15")
+		}
+	}
+
+	// Validate decimal separator
+	if c.DecimalSeparator != "." && c.DecimalSeparator != "," {
+		return errors.NewValidationError("decimal_separator", c.DecimalSeparator, "must be '.' or ','")
+	}
+
+	// Validate operand cap (may only tighten the global limit)
+	if c.MaxOperand <= 0 || c.MaxOperand > constants.MaxNumberInputValue {
+		return errors.NewValidationError(
+			"max_operand",
+			strconv.FormatFloat(c.MaxOperand, 'g', -1, 64),
+			fmt.Sprintf("must be greater than 0 and at most %g", constants.MaxNumberInputValue),
+		)
+	}
+
+	// Validate operand floor (may only tighten the global limit)
+	if c.MinOperand < constants.MinNumberInputValue || c.MinOperand >= c.MaxOperand {
+		return errors.NewValidationError(
+			"min_operand",
+			strconv.FormatFloat(c.MinOperand, 'g', -1, 64),
+			fmt.Sprintf("must be at least %g and less than max_operand", constants.MinNumberInputValue),
+		)
+	}
+
+	// Validate factorial ceiling (float64 overflows beyond 170!)
+	if c.MaxFactorialInput < 1 || c.MaxFactorialInput > constants.MaxFactorialInput {
+		return errors.NewValidationError(
+			"max_factorial_input",
+			strconv.Itoa(c.MaxFactorialInput),
+			fmt.Sprintf("must be between 1 and %d", constants.MaxFactorialInput),
+		)
+	}
+
+	// Validate factorial confirmation threshold (0 turns it off)
+	if c.FactorialConfirmAbove < 0 || c.FactorialConfirmAbove > constants.MaxFactorialInput {
+		return errors.NewValidationError(
+			"factorial_confirm_above",
+			strconv.Itoa(c.FactorialConfirmAbove),
+			fmt.Sprintf("must be between 0 and %d", constants.MaxFactorialInput),
+		)
+	}
+
+	// Validate modulo mode
+	if c.ModuloMode != constants.ModuloTruncated && c.ModuloMode != constants.ModuloEuclidean {
+		return errors.NewValidationError(
+			"modulo_mode",
+			c.ModuloMode,
+			fmt.Sprintf("must be %q or %q", constants.ModuloTruncated, constants.ModuloEuclidean),
+		)
+	}
+
+	// Validate time format
+	if err := validateTimeFormat(c.TimeFormat); err != nil {
+		return err
 	}
 
 	// Validate max history
 	if c.MaxHistory < 0 || c.MaxHistory > 10000 {
-		return errors.NewValidationError("max_history", string(rune(c.MaxHistory)), "must be between 0 and 10000")
+		return errors.NewValidationError("max_history", strconv.Itoa(c.MaxHistory), "must be between 0 and 10000")
+	}
+
+	// Validate output width
+	if c.OutputWidth < constants.MinOutputWidth || c.OutputWidth > constants.MaxOutputWidth {
+		return errors.NewValidationError(
+			"output_width",
+			strconv.Itoa(c.OutputWidth),
+			fmt.Sprintf("must be between %d and %d", constants.MinOutputWidth, constants.MaxOutputWidth),
+		)
+	}
+
+	// Validate fraction denominator
+	if c.MaxFractionDenominator < 1 || c.MaxFractionDenominator > constants.MaxFractionDenominator {
+		return errors.NewValidationError(
+			"max_fraction_denominator",
+			strconv.Itoa(c.MaxFractionDenominator),
+			fmt.Sprintf("must be between 1 and %d", constants.MaxFractionDenominator),
+		)
+	}
+
+	// Validate history byte cap
+	if c.MaxHistoryBytes < 0 {
+		return errors.NewValidationError("max_history_bytes", strconv.Itoa(c.MaxHistoryBytes), "must not be negative (0 means no limit)")
+	}
+
+	// Validate expression display width
+	if c.MaxExpressionDisplay < 0 {
+		return errors.NewValidationError("max_expression_display", strconv.Itoa(c.MaxExpressionDisplay), "must not be negative (0 shows full expressions)")
+	}
+
+	// Validate auto-save interval
+	if c.AutoSaveInterval < 0 || c.AutoSaveInterval > constants.MaxAutoSaveInterval {
+		return errors.NewValidationError(
+			"auto_save_interval",
+			strconv.Itoa(c.AutoSaveInterval),
+			fmt.Sprintf("must be between 0 and %d seconds", constants.MaxAutoSaveInterval),
+		)
+	}
+
+	// Validate input retries
+	if c.MaxInputRetries < 1 || c.MaxInputRetries > 10 {
+		return errors.NewValidationError("max_input_retries", strconv.Itoa(c.MaxInputRetries), "must be between 1 and 10")
+	}
+
+	// Validate pinned operations
+	for i, name := range c.PinnedOperations {
+		if _, ok := constants.LookupOperation(name); !ok {
+			return errors.NewValidationError(
+				fmt.Sprintf("pinned_operations[%d]", i),
+				name,
+				"unknown operation (use a name like \"divide\" or a symbol like \"/\")",
+			)
+		}
+	}
+
+	// Reject contradictory combinations of otherwise valid settings
+	for _, conflict := range conflictingOptions {
+		if conflict.enabled(c) {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s + %s", conflict.fields[0], conflict.fields[1]),
+				"true, true",
+				"cannot both be enabled: "+conflict.reason,
+			)
+		}
 	}
 
 	return nil
 }
 
+// conflictingOptions lists pairs of settings that contradict each other.
+// Only settings that can't be toggled from the settings menu belong here,
+// so the menu can never save a config that fails to load.
+var conflictingOptions = []struct {
+	fields  [2]string          // JSON names of the conflicting settings
+	enabled func(*Config) bool // Reports whether both are enabled
+	reason  string             // Why they can't be combined
+}{
+	{
+		fields:  [2]string{"scientific_mode", "thousand_sep"},
+		enabled: func(c *Config) bool { return c.ScientificMode && c.ThousandSep },
+		reason:  "scientific notation has no digit groups to separate",
+	},
+	{
+		fields:  [2]string{"history_read_only", "prompt_for_label"},
+		enabled: func(c *Config) bool { return c.HistoryReadOnly && c.PromptForLabel },
+		reason:  "labels can't be recorded in a read-only history",
+	},
+}
+
+// validateTimeFormat checks a Go time layout by formatting a sample time and
+// parsing it back. A layout without any date/time fields formats to itself,
+// which is treated as invalid since every entry would show the same text.
+func validateTimeFormat(layout string) error {
+	sample := time.Date(2024, time.March, 15, 13, 45, 30, 0, time.UTC)
+	formatted := sample.Format(layout)
+
+	if layout == "" || formatted == layout {
+		return errors.NewValidationError("time_format", layout, "must contain date or time fields (e.g. 15:04:05)")
+	}
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return errors.NewValidationError("time_format", layout, "not a valid Go time layout")
+	}
+	return nil
+}
+
 // Reset resets the configuration to default values.
 func (c *Config) Reset() {
 	defaultCfg := DefaultConfig()
@@ -151,6 +426,17 @@ func (c *Config) Reset() {
 	c.HistoryPath = historyPath
 }
 
+// PrecisionFor returns the decimal places to show for results of op: its
+// entry in OperationPrecision when one names it, otherwise Precision.
+func (c *Config) PrecisionFor(op constants.Operation) int {
+	for name, precision := range c.OperationPrecision {
+		if named, ok := constants.LookupOperation(name); ok && named == op {
+			return precision
+		}
+	}
+	return c.Precision
+}
+
 // Clone creates a deep copy of the configuration.
 // This demonstrates pointer handling and value copying.
 func (c *Config) Clone() *Config {
@@ -166,5 +452,12 @@ func (c *Config) Clone() *Config {
 		clone.HistoryPath = &path
 	}
 
+	// Copy slices and maps so editing the clone's pins or overrides can't
+	// change the original
+	if c.PinnedOperations != nil {
+		clone.PinnedOperations = append([]string(nil), c.PinnedOperations...)
+	}
+	clone.OperationPrecision = maps.Clone(c.OperationPrecision)
+
 	return &clone
 }
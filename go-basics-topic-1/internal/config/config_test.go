@@ -4,8 +4,11 @@ package config
 
 import (
 	"cli-calculator/internal/constants"
+	"cli-calculator/internal/errors"
+	stderrors "errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -79,6 +82,198 @@ func TestConfigValidation(t *testing.T) {
 			},
 			hasError: true,
 		},
+		{
+			name: "max operand above global cap",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxOperand = constants.MaxNumberInputValue * 10
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "zero input retries",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxInputRetries = 0
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "negative expression display width",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxExpressionDisplay = -1
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "zero fraction denominator",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxFractionDenominator = 0
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "output width too narrow",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.OutputWidth = constants.MinOutputWidth - 1
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "output width too wide",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.OutputWidth = constants.MaxOutputWidth + 1
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "negative auto-save interval",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.AutoSaveInterval = -1
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "min operand floor of zero",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MinOperand = 0
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "min operand not below max operand",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxOperand = 100
+				cfg.MinOperand = 100
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "lowered max factorial input",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxFactorialInput = 20
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "max factorial input above overflow bound",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxFactorialInput = 171
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "factorial confirmation disabled",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.FactorialConfirmAbove = 0
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "negative factorial confirmation threshold",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.FactorialConfirmAbove = -1
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "max operand zero",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxOperand = 0
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "lowered max operand",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.MaxOperand = 1000
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "invalid decimal separator",
+			config: &Config{
+				Precision:        2,
+				MaxHistory:       100,
+				DecimalSeparator: ";",
+			},
+			hasError: true,
+		},
+		{
+			name: "euclidean modulo mode",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.ModuloMode = "euclidean"
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "scientific mode alone",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.ScientificMode = true
+				return cfg
+			}(),
+			hasError: false,
+		},
+		{
+			name: "scientific mode with thousand separator",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.ScientificMode = true
+				cfg.ThousandSep = true
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "read-only history with label prompt",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.HistoryReadOnly = true
+				cfg.PromptForLabel = true
+				return cfg
+			}(),
+			hasError: true,
+		},
+		{
+			name: "invalid modulo mode",
+			config: func() *Config {
+				cfg := DefaultConfig()
+				cfg.ModuloMode = "floored"
+				return cfg
+			}(),
+			hasError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +293,124 @@ func TestConfigValidation(t *testing.T) {
 	}
 }
 
+// TestConfigConflictNamesFields tests that a conflict error names both
+// settings involved.
+func TestConfigConflictNamesFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		enable func(cfg *Config)
+		fields []string
+	}{
+		{"scientific and thousand separator", func(cfg *Config) { cfg.ScientificMode, cfg.ThousandSep = true, true }, []string{"scientific_mode", "thousand_sep"}},
+		{"read-only and label prompt", func(cfg *Config) { cfg.HistoryReadOnly, cfg.PromptForLabel = true, true }, []string{"history_read_only", "prompt_for_label"}},
+	}
+
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.enable(cfg)
+
+		var validationErr *errors.ValidationError
+		if err := cfg.Validate(); !stderrors.As(err, &validationErr) {
+			t.Fatalf("%s: expected ValidationError, got %v", tt.name, err)
+		}
+		for _, field := range tt.fields {
+			if !strings.Contains(validationErr.Error(), field) {
+				t.Errorf("%s: expected error to name %s, got %q", tt.name, field, validationErr.Error())
+			}
+		}
+	}
+}
+
+// TestConfigPinnedOperations tests validating pinned operation names.
+func TestConfigPinnedOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		pinned   []string
+		field    string // Field named in the error; empty means valid
+	}{
+		{"names and symbols", []string{"divide", "^", "Factorial"}, ""},
+		{"none pinned", nil, ""},
+		{"unknown name", []string{"divide", "logarithm"}, "pinned_operations[1]"},
+		{"empty name", []string{""}, "pinned_operations[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.PinnedOperations = tt.pinned
+
+			err := cfg.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				return
+			}
+
+			var validationErr *errors.ValidationError
+			if !stderrors.As(err, &validationErr) {
+				t.Fatalf("%s: expected ValidationError, got %v", tt.name, err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("%s: expected field %s, got %s", tt.name, tt.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+// TestConfigOperationPrecision tests validating per-operation precision
+// overrides and looking them up.
+func TestConfigOperationPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]int
+		field     string // Field named in the error; empty means valid
+	}{
+		{"names and symbols", map[string]int{"divide": 6, "+": 0, "Power": 15}, ""},
+		{"none", nil, ""},
+		{"unknown operation", map[string]int{"logarithm": 4}, `operation_precision["logarithm"]`},
+		{"too many decimals", map[string]int{"divide": 16}, `operation_precision["divide"]`},
+		{"negative", map[string]int{"add": -1}, `operation_precision["add"]`},
+		{"same operation twice", map[string]int{"/": 4, "divide": 6}, `operation_precision["divide"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.OperationPrecision = tt.overrides
+
+			err := cfg.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				return
+			}
+
+			var validationErr *errors.ValidationError
+			if !stderrors.As(err, &validationErr) {
+				t.Fatalf("%s: expected ValidationError, got %v", tt.name, err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("%s: expected field %s, got %s", tt.name, tt.field, validationErr.Field)
+			}
+		})
+	}
+
+	cfg := DefaultConfig()
+	cfg.Precision = 2
+	cfg.OperationPrecision = map[string]int{"divide": 6, "+": 0}
+	for op, expected := range map[constants.Operation]int{
+		constants.OpDivision:       6,
+		constants.OpAddition:       0,
+		constants.OpMultiplication: 2,
+	} {
+		if got := cfg.PrecisionFor(op); got != expected {
+			t.Errorf("PrecisionFor(%s): expected %d, got %d", op, expected, got)
+		}
+	}
+}
+
 // TestConfigSaveAndLoad tests saving and loading configuration.
 func TestConfigSaveAndLoad(t *testing.T) {
 	// Create a temporary file
@@ -191,6 +504,22 @@ func TestConfigClone(t *testing.T) {
 			t.Error("ConfigPath pointers are the same (not deep copied)")
 		}
 	}
+
+	// Verify slices are copied too
+	cfg.PinnedOperations = []string{"divide"}
+	clone = cfg.Clone()
+	clone.PinnedOperations[0] = "add"
+	if cfg.PinnedOperations[0] != "divide" {
+		t.Error("Modifying clone's pinned operations affected original config")
+	}
+
+	// And maps
+	cfg.OperationPrecision = map[string]int{"divide": 6}
+	clone = cfg.Clone()
+	clone.OperationPrecision["divide"] = 1
+	if cfg.OperationPrecision["divide"] != 6 {
+		t.Error("Modifying clone's operation precision affected original config")
+	}
 }
 
 // TestLoadNonExistentConfig tests loading when config file doesn't exist.
@@ -212,3 +541,145 @@ func TestLoadNonExistentConfig(t *testing.T) {
 		t.Errorf("Expected default precision %d, got %d", constants.DefaultPrecision, cfg.Precision)
 	}
 }
+
+// TestLoadFromCustomPath tests loading configuration from a custom path.
+func TestLoadFromCustomPath(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "project_config.json")
+
+	// Write a config file with overridden values
+	data := []byte(`{"precision": 6, "show_welcome": false, "max_history": 25}`)
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom returned error: %v", err)
+	}
+
+	if cfg.Precision != 6 {
+		t.Errorf("Expected precision 6, got %d", cfg.Precision)
+	}
+	if cfg.ShowWelcome {
+		t.Error("Expected ShowWelcome to be false")
+	}
+	if cfg.MaxHistory != 25 {
+		t.Errorf("Expected MaxHistory 25, got %d", cfg.MaxHistory)
+	}
+
+	// Values missing from the file keep their defaults
+	if !cfg.SaveHistory {
+		t.Error("Expected SaveHistory to keep its default of true")
+	}
+
+	// The config remembers where it was loaded from
+	if cfg.ConfigPath == nil || *cfg.ConfigPath != configPath {
+		t.Errorf("Expected ConfigPath %q, got %v", configPath, cfg.ConfigPath)
+	}
+}
+
+// TestDefaultConfigXDGPaths tests that XDG base directories are honored.
+func TestDefaultConfigXDGPaths(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	cfg := DefaultConfig()
+
+	expectedConfig := filepath.Join(configHome, constants.ConfigFileName)
+	if *cfg.ConfigPath != expectedConfig {
+		t.Errorf("Expected config path %s, got %s", expectedConfig, *cfg.ConfigPath)
+	}
+
+	expectedHistory := filepath.Join(dataHome, constants.HistoryFileName)
+	if *cfg.HistoryPath != expectedHistory {
+		t.Errorf("Expected history path %s, got %s", expectedHistory, *cfg.HistoryPath)
+	}
+}
+
+// TestDefaultConfigPathFallback tests the home and current-directory fallbacks.
+func TestDefaultConfigPathFallback(t *testing.T) {
+	home := t.TempDir()
+
+	tests := []struct {
+		name      string
+		home      string
+		xdgConfig string
+		expected  string
+	}{
+		{"home directory", home, "", filepath.Join(home, constants.ConfigFileName)},
+		{"relative XDG ignored", home, "relative/dir", filepath.Join(home, constants.ConfigFileName)},
+		{"no home directory", "", "", constants.ConfigFileName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", tt.home)
+			t.Setenv("XDG_CONFIG_HOME", tt.xdgConfig)
+			t.Setenv("XDG_DATA_HOME", "")
+
+			cfg := DefaultConfig()
+			if *cfg.ConfigPath != tt.expected {
+				t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, *cfg.ConfigPath)
+			}
+		})
+	}
+}
+
+// TestTimeFormatValidation tests validation of the history time layout.
+func TestTimeFormatValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   string
+		hasError bool
+	}{
+		{"default layout", constants.DefaultTimeFormat, false},
+		{"date and time", "2006-01-02 15:04", false},
+		{"kitchen", "3:04PM", false},
+		{"no layout fields", "YYYY-MM-DD", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.TimeFormat = tt.layout
+			err := cfg.Validate()
+
+			if tt.hasError && err == nil {
+				t.Errorf("%s: expected error, got nil", tt.name)
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// TestLoadFromRejectsInvalidTimeFormat tests that an invalid layout is rejected on load.
+func TestLoadFromRejectsInvalidTimeFormat(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"precision": 2, "time_format": "hh:mm"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	if _, err := LoadFrom(configPath); err == nil {
+		t.Error("Expected error for invalid time format, got nil")
+	}
+}
+
+// TestLoadFromDirectory tests the clear error when the config path is a directory.
+func TestLoadFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadFrom(dir)
+	if !stderrors.Is(err, errors.ErrIsDirectory) {
+		t.Fatalf("Expected ErrIsDirectory, got %v", err)
+	}
+
+	var fileErr *errors.FileError
+	if !stderrors.As(err, &fileErr) || fileErr.Path != dir {
+		t.Errorf("Expected a FileError for %s, got %v", dir, err)
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"cli-calculator/internal/constants"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Schema is a JSON Schema document describing the config file.
+type Schema struct {
+	Schema               string                    `json:"$schema"`
+	Title                string                    `json:"title"`
+	Type                 string                    `json:"type"`
+	Properties           map[string]SchemaProperty `json:"properties"`
+	AdditionalProperties bool                      `json:"additionalProperties"`
+}
+
+// SchemaProperty describes a single config field.
+// Pointers keep absent bounds out of the JSON, since 0 is a real bound.
+type SchemaProperty struct {
+	Type             string   `json:"type"`
+	Default          any      `json:"default"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	Enum             []string `json:"enum,omitempty"`
+	Description      string   `json:"description,omitempty"`
+}
+
+// bound returns a pointer to v for the optional SchemaProperty limits.
+func bound(v float64) *float64 {
+	return &v
+}
+
+// fieldConstraints mirrors the checks in Validate for each constrained field.
+// Fields not listed here accept any value of their type.
+var fieldConstraints = map[string]SchemaProperty{
+	"precision":                {Minimum: bound(0), Maximum: bound(15)},
+	"operation_precision":      {Description: "decimal places (0-15) per operation name or symbol, e.g. {\"divide\": 6}; others use precision"},
+	"banner_text":              {Description: "replaces the welcome banner when set; wrapped to fit the box"},
+	"decimal_separator":        {Enum: []string{".", ","}},
+	"time_format":              {Description: "Go time layout, e.g. \"15:04:05\""},
+	"max_expression_display":   {Minimum: bound(0), Description: "0 shows full expressions"},
+	"output_width":             {Minimum: bound(constants.MinOutputWidth), Maximum: bound(constants.MaxOutputWidth), Description: "columns for dividers, boxes, and wrapped help text"},
+	"max_fraction_denominator": {Minimum: bound(1), Maximum: bound(constants.MaxFractionDenominator), Description: "largest denominator show_fraction uses; results with no close fraction show none"},
+	"max_history":              {Minimum: bound(0), Maximum: bound(10000)},
+	"max_history_bytes":        {Minimum: bound(0), Description: "largest history file in bytes; the oldest entries are dropped on save to fit; 0 means no limit"},
+	"auto_save_interval":       {Minimum: bound(0), Maximum: bound(constants.MaxAutoSaveInterval), Description: "seconds; 0 saves after every calculation"},
+	"chain_results":            {Description: "after a menu calculation, prompt for more operations on its result; off keeps scripted input simple"},
+	"audit_log_path":           {Description: "file to append a JSON line per calculation to; never trimmed or cleared; empty disables"},
+	"max_input_retries":        {Minimum: bound(1), Maximum: bound(10)},
+	"max_operand":              {ExclusiveMinimum: bound(0), Maximum: bound(constants.MaxNumberInputValue)},
+	"min_operand":              {Minimum: bound(constants.MinNumberInputValue), Description: "must be less than max_operand"},
+	"max_factorial_input":      {Minimum: bound(1), Maximum: bound(constants.MaxFactorialInput)},
+	"factorial_confirm_above":  {Minimum: bound(0), Maximum: bound(constants.MaxFactorialInput), Description: "factorials of larger n ask for confirmation first; 0 never asks"},
+	"modulo_mode":              {Enum: []string{constants.ModuloTruncated, constants.ModuloEuclidean}},
+	"pinned_operations":        {Description: "operation names or symbols, e.g. [\"divide\", \"^\"]"},
+}
+
+// BuildSchema describes every JSON field of Config with its type, default,
+// and valid range. Field names and defaults come from reflection, so new
+// fields appear automatically; ranges come from fieldConstraints.
+// This demonstrates reading struct tags with the reflect package.
+func BuildSchema() Schema {
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+	configType := defaults.Type()
+
+	schema := Schema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Title:      constants.AppName + " configuration",
+		Type:       "object",
+		Properties: make(map[string]SchemaProperty),
+	}
+
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue // Not stored in the config file
+		}
+
+		prop := fieldConstraints[name]
+		prop.Type = schemaType(field.Type.Kind())
+		prop.Default = defaults.Field(i).Interface()
+		schema.Properties[name] = prop
+	}
+
+	// Note settings that Validate rejects in combination
+	for _, conflict := range conflictingOptions {
+		for i, name := range conflict.fields {
+			prop := schema.Properties[name]
+			prop.Description = joinDescription(prop.Description, "cannot be enabled together with "+conflict.fields[1-i])
+			schema.Properties[name] = prop
+		}
+	}
+
+	return schema
+}
+
+// joinDescription appends note to an existing description.
+func joinDescription(description, note string) string {
+	if description == "" {
+		return note
+	}
+	return description + "; " + note
+}
+
+// schemaType maps a Go kind to its JSON Schema type name.
+func schemaType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// WriteSchema writes the config JSON Schema to w as indented JSON.
+func WriteSchema(w io.Writer) error {
+	data, err := json.MarshalIndent(BuildSchema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config schema: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestWriteSchemaPrecision tests that the schema describes precision with
+// its default and 0-15 range.
+func TestWriteSchemaPrecision(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSchema(&buf); err != nil {
+		t.Fatalf("WriteSchema returned error: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Type    string   `json:"type"`
+			Default any      `json:"default"`
+			Minimum *float64 `json:"minimum"`
+			Maximum *float64 `json:"maximum"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	precision, ok := schema.Properties["precision"]
+	if !ok {
+		t.Fatal("Expected schema to describe precision")
+	}
+	if precision.Type != "integer" {
+		t.Errorf("Expected precision type integer, got %q", precision.Type)
+	}
+	if precision.Default != float64(2) {
+		t.Errorf("Expected precision default 2, got %v", precision.Default)
+	}
+	if precision.Minimum == nil || *precision.Minimum != 0 {
+		t.Errorf("Expected precision minimum 0, got %v", precision.Minimum)
+	}
+	if precision.Maximum == nil || *precision.Maximum != 15 {
+		t.Errorf("Expected precision maximum 15, got %v", precision.Maximum)
+	}
+}
+
+// TestBuildSchemaCoversConfig tests that every saved field is described,
+// path fields are left out, and every constraint names a real field.
+func TestBuildSchemaCoversConfig(t *testing.T) {
+	schema := BuildSchema()
+
+	data, err := json.Marshal(DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	var saved map[string]any
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+
+	for name := range saved {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("Expected schema to describe %q", name)
+		}
+	}
+	if len(schema.Properties) != len(saved) {
+		t.Errorf("Expected %d properties, got %d", len(saved), len(schema.Properties))
+	}
+	for name := range fieldConstraints {
+		if _, ok := saved[name]; !ok {
+			t.Errorf("Constraint for %q does not match any config field", name)
+		}
+	}
+}
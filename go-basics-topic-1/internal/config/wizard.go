@@ -0,0 +1,84 @@
+package config
+
+import (
+	"cli-calculator/internal/util"
+	"cli-calculator/internal/validation"
+	"fmt"
+	"strconv"
+)
+
+// RunWizard walks a first-time user through the main preferences (result
+// precision, colored output, and history recording) and returns the
+// resulting configuration, starting from a clone of base so settings made
+// before the wizard (e.g. command-line flags) are kept. Pressing Enter keeps
+// the value shown in brackets; invalid answers are reported and asked again.
+// The caller decides where to save the result.
+func RunWizard(u *util.IO, base *Config) (*Config, error) {
+	cfg := base.Clone()
+
+	fmt.Fprintln(u.Out, "Welcome! Let's set up the calculator. Press Enter to keep a default.")
+
+	precision, err := askPrecision(u, cfg.Precision)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Precision = precision
+
+	if cfg.ColorOutput, err = askYesNo(u, "Use colored output?", cfg.ColorOutput); err != nil {
+		return nil, err
+	}
+	if cfg.SaveHistory, err = askYesNo(u, "Save calculation history?", cfg.SaveHistory); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// askPrecision reads a precision until it passes validation.ValidatePrecision.
+func askPrecision(u *util.IO, def int) (int, error) {
+	for {
+		input, err := u.GetUserInput(fmt.Sprintf("Decimal places for results (0-15) [%d]: ", def))
+		if err != nil {
+			return 0, err
+		}
+		if input == "" {
+			return def, nil
+		}
+
+		precision, err := strconv.Atoi(input)
+		if err == nil {
+			err = validation.ValidatePrecision(precision)
+		}
+		if err == nil {
+			return precision, nil
+		}
+		fmt.Fprintf(u.Out, "✗ Error: %q is not a whole number from 0 to 15\n", input)
+	}
+}
+
+// askYesNo reads an answer until validation.ValidateYesNo accepts it.
+func askYesNo(u *util.IO, question string, def bool) (bool, error) {
+	choices := "(y/N)"
+	if def {
+		choices = "(Y/n)"
+	}
+
+	for {
+		input, err := u.GetUserInput(question + " " + choices + ": ")
+		if err != nil {
+			return false, err
+		}
+		if input == "" {
+			return def, nil
+		}
+
+		answer, err := validation.ValidateYesNo(input)
+		if err == nil {
+			return answer, nil
+		}
+		fmt.Fprintf(u.Out, "✗ Error: %v\n", err)
+	}
+}
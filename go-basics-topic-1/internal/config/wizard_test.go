@@ -0,0 +1,53 @@
+package config
+
+import (
+	"bytes"
+	"cli-calculator/internal/constants"
+	"cli-calculator/internal/util"
+	"strings"
+	"testing"
+)
+
+// TestRunWizard tests the first-run setup with scripted answers.
+func TestRunWizard(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		precision   int
+		color       bool
+		saveHistory bool
+		hasError    bool
+	}{
+		{"all defaults", "\n\n\n", constants.DefaultPrecision, false, true, false},
+		{"custom answers", "4\ny\nno\n", 4, true, false, false},
+		{"invalid answers asked again", "20\nabc\n3\nmaybe\nyes\n\n", 3, true, true, false},
+		{"input ends early", "4\n", 0, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cfg, err := RunWizard(util.NewIO(strings.NewReader(tt.input), &out), DefaultConfig())
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			if cfg.Precision != tt.precision {
+				t.Errorf("%s: expected precision %d, got %d", tt.name, tt.precision, cfg.Precision)
+			}
+			if cfg.ColorOutput != tt.color {
+				t.Errorf("%s: expected color %v, got %v", tt.name, tt.color, cfg.ColorOutput)
+			}
+			if cfg.SaveHistory != tt.saveHistory {
+				t.Errorf("%s: expected save history %v, got %v", tt.name, tt.saveHistory, cfg.SaveHistory)
+			}
+		})
+	}
+}
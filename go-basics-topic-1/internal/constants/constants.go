@@ -2,6 +2,8 @@
 // This demonstrates proper constant declaration and the iota identifier.
 package constants
 
+import "strings"
+
 // ExitCode represents application exit status codes.
 // Using iota to create enumerated constants starting from 0.
 type ExitCode int
@@ -27,8 +29,53 @@ const (
 	OpSquareRoot
 	OpModulo
 	OpFactorial
+	OpPercentChange
+	OpRatio
+	OpRound
+	OpAverage
 )
 
+// AllOperations lists every supported operation in menu order.
+// OpUnknown is deliberately excluded.
+var AllOperations = []Operation{
+	OpAddition,
+	OpSubtraction,
+	OpMultiplication,
+	OpDivision,
+	OpPower,
+	OpSquareRoot,
+	OpModulo,
+	OpFactorial,
+	OpPercentChange,
+	OpRatio,
+	OpRound,
+	OpAverage,
+}
+
+// OperationAliases maps the symbols and words users may type to operations.
+// Keys are lower-case; use LookupOperation for case-insensitive lookups.
+var OperationAliases = map[string]Operation{
+	"+": OpAddition, "add": OpAddition, "plus": OpAddition, "sum": OpAddition, "addition": OpAddition,
+	"-": OpSubtraction, "sub": OpSubtraction, "subtract": OpSubtraction, "minus": OpSubtraction, "subtraction": OpSubtraction,
+	"*": OpMultiplication, "x": OpMultiplication, "mul": OpMultiplication, "multiply": OpMultiplication, "times": OpMultiplication, "multiplication": OpMultiplication,
+	"/": OpDivision, "div": OpDivision, "divide": OpDivision, "over": OpDivision, "division": OpDivision,
+	"^": OpPower, "pow": OpPower, "power": OpPower,
+	"√": OpSquareRoot, "sqrt": OpSquareRoot, "root": OpSquareRoot, "squareroot": OpSquareRoot,
+	"%": OpModulo, "mod": OpModulo, "modulo": OpModulo, "rem": OpModulo, "remainder": OpModulo,
+	"!": OpFactorial, "fact": OpFactorial, "factorial": OpFactorial,
+	"pct": OpPercentChange, "change": OpPercentChange, "percentchange": OpPercentChange,
+	":": OpRatio, "ratio": OpRatio,
+	"≈": OpRound, "round": OpRound, "rnd": OpRound,
+	"μ": OpAverage, "avg": OpAverage, "average": OpAverage, "mean": OpAverage,
+}
+
+// LookupOperation resolves an alias such as "plus" or "+" to an operation.
+// Matching ignores case and surrounding whitespace.
+func LookupOperation(alias string) (Operation, bool) {
+	op, ok := OperationAliases[strings.ToLower(strings.TrimSpace(alias))]
+	return op, ok
+}
+
 // String returns the string representation of an operation.
 func (o Operation) String() string {
 	switch o {
@@ -48,6 +95,14 @@ func (o Operation) String() string {
 		return "Modulo"
 	case OpFactorial:
 		return "Factorial"
+	case OpPercentChange:
+		return "Percent Change"
+	case OpRatio:
+		return "Ratio"
+	case OpRound:
+		return "Round"
+	case OpAverage:
+		return "Average"
 	default:
 		return "Unknown"
 	}
@@ -72,11 +127,73 @@ func (o Operation) Symbol() string {
 		return "%"
 	case OpFactorial:
 		return "!"
+	case OpPercentChange:
+		return "%Δ"
+	case OpRatio:
+		return ":"
+	case OpRound:
+		return "≈"
+	case OpAverage:
+		return "μ"
 	default:
 		return "?"
 	}
 }
 
+// OperationDescription returns a one-line description of an operation with an
+// example, e.g. "remainder of division; 10 % 3 = 1". OpUnknown returns "".
+func OperationDescription(op Operation) string {
+	switch op {
+	case OpAddition:
+		return "sum of two or more numbers; 2 + 3 = 5"
+	case OpSubtraction:
+		return "difference of two numbers; 10 - 4 = 6"
+	case OpMultiplication:
+		return "product of two or more numbers; 6 * 7 = 42"
+	case OpDivision:
+		return "quotient of two numbers (the divisor cannot be zero); 10 / 4 = 2.5"
+	case OpPower:
+		return "first number raised to the power of the second; 2 ^ 10 = 1024"
+	case OpSquareRoot:
+		return "number that multiplied by itself gives the input; √16 = 4"
+	case OpModulo:
+		return "remainder of division; 10 % 3 = 1"
+	case OpFactorial:
+		return "product of all whole numbers from 1 to n; 5! = 120"
+	case OpPercentChange:
+		return "percentage change from the first number to the second; 200 → 250 = 25%"
+	case OpRatio:
+		return "ratio of two numbers in lowest terms; 4:6 = 2:3"
+	case OpRound:
+		return "first number rounded to the second's count of decimal places, halves away from zero; round(3.14159, 2) = 3.14"
+	case OpAverage:
+		return "arithmetic mean of one or more numbers; avg(2, 4, 6) = 4"
+	default:
+		return ""
+	}
+}
+
+// OperationComplexity returns the time complexity of an operation as an
+// educational note, e.g. "O(n)" for factorial. OpUnknown returns "".
+func OperationComplexity(op Operation) string {
+	switch op {
+	case OpAddition, OpSubtraction, OpMultiplication, OpAverage:
+		return "O(n) in the number of operands"
+	case OpDivision, OpModulo, OpPercentChange, OpRound:
+		return "O(1)"
+	case OpPower:
+		return "O(1) (math.Pow)"
+	case OpSquareRoot:
+		return "O(1) (hardware square root)"
+	case OpFactorial:
+		return "O(n) multiplications"
+	case OpRatio:
+		return "O(log n) (Euclid's gcd)"
+	default:
+		return ""
+	}
+}
+
 // MenuOption represents main menu choices.
 type MenuOption uint8
 
@@ -88,6 +205,7 @@ const (
 	MenuSettings
 	MenuHelp
 	MenuExit
+	MenuQuickOperations // Added after Exit so scripted input keeps the older numbers
 )
 
 // LogLevel represents logging severity levels.
@@ -124,14 +242,47 @@ const (
 	HistoryFileName   = ".calculator_history.json"
 	MaxHistoryEntries = 100
 	DefaultPrecision  = 2
+	DefaultEpsilon    = 1e-9 // Tolerance used when comparing float results
+	DefaultMaxRetries = 3    // Attempts allowed for each number prompt
+
+	MaxAutoSaveInterval = 3600 // Longest auto-save interval in seconds (one hour)
+
+	MaxRoundDecimals = 15 // Most decimal places the round operation accepts
+
+	DefaultMaxExpressionDisplay = 40 // Characters of an expression shown in the history list
+
+	DefaultOutputWidth = 56  // Columns used by dividers, boxes, and wrapped help text
+	MinOutputWidth     = 30  // Narrowest width that still fits the welcome title
+	MaxOutputWidth     = 200 // Widest output width accepted
+
+	DefaultMaxFractionDenominator = 1000    // Largest denominator shown for results as fractions
+	MaxFractionDenominator        = 1000000 // Largest max_fraction_denominator accepted
+
+	DefaultDecimalSeparator = "."
+	DefaultTimeFormat       = "15:04:05" // Go layout for history timestamps
+)
+
+// Modulo modes decide the sign of a remainder
+const (
+	ModuloTruncated = "truncated" // Sign follows the dividend, like math.Mod: -1 % 3 = -1
+	ModuloEuclidean = "euclidean" // Never negative: -1 % 3 = 2
+)
+
+// Output formats for one-shot results
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
 )
 
 // Validation constants
 const (
 	MinMenuOption       = 1
-	MaxMenuOption       = 7
+	MaxMenuOption       = 8
 	MinBasicCalcOption  = 1
 	MaxBasicCalcOption  = 4
 	MaxNumberInputValue = 1e15  // Maximum safe number for calculations
 	MinNumberInputValue = -1e15 // Minimum safe number for calculations
+	MaxFactorialInput   = 170   // Largest n whose factorial fits in a float64
+
+	DefaultFactorialConfirmAbove = 100 // Factorials of larger n ask for confirmation first
 )
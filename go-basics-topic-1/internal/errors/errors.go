@@ -5,6 +5,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 )
 
 // Sentinel errors - predefined errors that can be compared using errors.Is()
@@ -19,6 +20,15 @@ var (
 	ErrFileWriteFailed   = errors.New("failed to write file")
 	ErrConfigInvalid     = errors.New("configuration is invalid")
 	ErrHistoryFull       = errors.New("history is full")
+	ErrNoEditor          = errors.New("no editor configured (set $VISUAL or $EDITOR)")
+	ErrNoClipboard       = errors.New("no clipboard tool found (install pbcopy, xclip, or wl-copy)")
+	ErrIsDirectory       = errors.New("path is a directory, not a file")
+	ErrInternal          = errors.New("unexpected internal error")
+	ErrOutputClosed      = errors.New("output closed by the reader")
+
+	// ErrUnbalancedParens wraps ErrInvalidInput, so code that only checks
+	// for invalid input still treats it as such.
+	ErrUnbalancedParens = fmt.Errorf("unbalanced parentheses: %w", ErrInvalidInput)
 )
 
 // ValidationError represents an input validation error with context.
@@ -91,6 +101,20 @@ func (e *FileError) Unwrap() error {
 	return e.Err
 }
 
+// IsNotExist reports whether the file or a directory on its path is missing.
+// Like os.IsNotExist it recognizes the os error kind, but it also looks
+// through errors wrapped inside Err.
+func (e *FileError) IsNotExist() bool {
+	return errors.Is(e.Err, fs.ErrNotExist)
+}
+
+// IsPermission reports whether the operation was denied by file permissions.
+// Like os.IsPermission it recognizes the os error kind, but it also looks
+// through errors wrapped inside Err.
+func (e *FileError) IsPermission() bool {
+	return errors.Is(e.Err, fs.ErrPermission)
+}
+
 // NewFileError creates a new FileError.
 func NewFileError(path, operation string, err error) *FileError {
 	return &FileError{
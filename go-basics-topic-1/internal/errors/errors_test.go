@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestFileErrorClassifiers tests IsNotExist and IsPermission for each kind
+// of underlying error, including wrapped ones.
+func TestFileErrorClassifiers(t *testing.T) {
+	_, openErr := os.Open(filepath.Join(t.TempDir(), "missing.json"))
+
+	tests := []struct {
+		name         string
+		err          error
+		isNotExist   bool
+		isPermission bool
+	}{
+		{"real missing file", openErr, true, false},
+		{"path error not exist", &fs.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}, true, false},
+		{"path error permission", &fs.PathError{Op: "open", Path: "x", Err: syscall.EACCES}, false, true},
+		{"sentinel not exist", fs.ErrNotExist, true, false},
+		{"sentinel permission", os.ErrPermission, false, true},
+		{"wrapped not exist", fmt.Errorf("loading: %w", fs.ErrNotExist), true, false},
+		{"wrapped permission", fmt.Errorf("saving: %w", syscall.EPERM), false, true},
+		{"is a directory", ErrIsDirectory, false, false},
+		{"other error", errors.New("disk full"), false, false},
+		{"no underlying error", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileErr := NewFileError("history.json", "read", tt.err)
+
+			if got := fileErr.IsNotExist(); got != tt.isNotExist {
+				t.Errorf("%s: expected IsNotExist %v, got %v", tt.name, tt.isNotExist, got)
+			}
+			if got := fileErr.IsPermission(); got != tt.isPermission {
+				t.Errorf("%s: expected IsPermission %v, got %v", tt.name, tt.isPermission, got)
+			}
+		})
+	}
+}
+
+// TestFileErrorClassifiersThroughWrap tests that callers can reach the
+// classifiers with errors.As after the FileError itself was wrapped.
+func TestFileErrorClassifiersThroughWrap(t *testing.T) {
+	err := Wrap(NewFileError("config.json", "read", fs.ErrNotExist), "failed to load config")
+
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) {
+		t.Fatalf("Expected a FileError in %v", err)
+	}
+	if !fileErr.IsNotExist() {
+		t.Error("Expected IsNotExist to be true")
+	}
+}
@@ -0,0 +1,166 @@
+package history
+
+import (
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"encoding/csv"
+	stderrors "errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader lists the CSV columns in the order they are written.
+var csvHeader = []string{"timestamp", "operation", "expression", "result", "success", "error"}
+
+// csvColumns renders each exportable field of an entry, keyed by the column
+// name (the same as the entry's JSON field name).
+var csvColumns = map[string]func(Entry) string{
+	"timestamp":   func(e Entry) string { return e.Timestamp.Format(time.RFC3339Nano) },
+	"operation":   func(e Entry) string { return e.Operation },
+	"expression":  func(e Entry) string { return e.Expression },
+	"result":      func(e Entry) string { return strconv.FormatFloat(e.Result, 'g', -1, 64) },
+	"success":     func(e Entry) string { return strconv.FormatBool(e.Success) },
+	"error":       func(e Entry) string { return e.Error },
+	"duration_ns": func(e Entry) string { return strconv.FormatInt(int64(e.Duration), 10) },
+	"label":       func(e Entry) string { return e.Label },
+	"count":       func(e Entry) string { return strconv.Itoa(e.Times()) },
+}
+
+// CSVColumns returns the column names ExportCSVWithColumns accepts, sorted.
+func CSVColumns() []string {
+	names := make([]string, 0, len(csvColumns))
+	for name := range csvColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExportCSV writes all entries as CSV with a header row.
+// This demonstrates the encoding/csv package and the io.Writer interface.
+func (h *History) ExportCSV(w io.Writer) error {
+	return h.ExportCSVWithColumns(w, csvHeader)
+}
+
+// ExportCSVWithColumns writes all entries as CSV with only the given
+// columns, in the given order, under a header row of their names. Columns
+// are checked before anything is written, so an unknown or repeated name
+// returns a ValidationError and leaves w untouched.
+func (h *History) ExportCSVWithColumns(w io.Writer, columns []string) error {
+	if len(columns) == 0 {
+		return errors.NewValidationError("csv_columns", "", "at least one column is required")
+	}
+
+	render := make([]func(Entry) string, len(columns))
+	seen := make(map[string]bool, len(columns))
+	for i, name := range columns {
+		column, ok := csvColumns[name]
+		if !ok {
+			return errors.NewValidationError("csv_columns", name, "unknown column (known: "+strings.Join(CSVColumns(), ", ")+")")
+		}
+		if seen[name] {
+			return errors.NewValidationError("csv_columns", name, "column listed more than once")
+		}
+		seen[name] = true
+		render[i] = column
+	}
+
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(columns); err != nil {
+		return errors.WrapWithContext(err, "failed to write CSV header")
+	}
+
+	record := make([]string, len(columns))
+	for _, entry := range h.GetAll() {
+		for i, column := range render {
+			record[i] = column(entry)
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.WrapWithContext(err, "failed to write CSV row")
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportCSV reads entries in the ExportCSV format and appends them to history,
+// respecting MaxSize. Malformed rows are skipped and counted in a warning
+// instead of aborting the import. It returns the number of entries imported.
+func (h *History) ImportCSV(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Check column counts ourselves so bad rows can be skipped
+
+	imported, skipped := 0, 0
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// A parse error only affects the current row
+			var parseErr *csv.ParseError
+			if stderrors.As(err, &parseErr) {
+				skipped++
+				continue
+			}
+			return imported, errors.WrapWithContext(err, "failed to read CSV")
+		}
+
+		// Skip the header row
+		if line == 1 && len(record) > 0 && record[0] == csvHeader[0] {
+			continue
+		}
+
+		entry, err := parseCSVRecord(record)
+		if err != nil {
+			logger.Debug("Skipping CSV line %d: %v", line, err)
+			skipped++
+			continue
+		}
+
+		h.Add(entry)
+		imported++
+	}
+
+	if skipped > 0 {
+		logger.Warn("Skipped %d malformed CSV row(s) during history import", skipped)
+	}
+
+	return imported, nil
+}
+
+// parseCSVRecord converts a CSV record into an Entry.
+func parseCSVRecord(record []string) (Entry, error) {
+	if len(record) != len(csvHeader) {
+		return Entry{}, errors.NewValidationError("csv_row", strconv.Itoa(len(record)), "wrong number of columns")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, record[0])
+	if err != nil {
+		return Entry{}, errors.NewValidationError("timestamp", record[0], "not a valid RFC 3339 time")
+	}
+
+	result, err := strconv.ParseFloat(record[3], 64)
+	if err != nil {
+		return Entry{}, errors.NewValidationError("result", record[3], "not a valid number")
+	}
+
+	success, err := strconv.ParseBool(record[4])
+	if err != nil {
+		return Entry{}, errors.NewValidationError("success", record[4], "not a valid boolean")
+	}
+
+	return Entry{
+		Timestamp:  timestamp,
+		Operation:  record[1],
+		Expression: record[2],
+		Result:     result,
+		Success:    success,
+		Error:      record[5],
+	}, nil
+}
@@ -0,0 +1,147 @@
+// Package history provides CSV import and export with tests.
+package history
+
+import (
+	"bytes"
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"strings"
+	"testing"
+)
+
+// TestImportCSV tests importing rows while skipping a malformed one.
+func TestImportCSV(t *testing.T) {
+	input := strings.Join([]string{
+		"timestamp,operation,expression,result,success,error",
+		"2024-01-02T10:00:00Z,Addition,2.00 + 3.00,5,true,",
+		"2024-01-02T10:01:00Z,Division,1.00 / 0.00,0,false,division by zero",
+		"not-a-time,Power,2.00 ^ 3.00,8,true,",
+		"2024-01-02T10:02:00Z,Multiplication,4.00 * 5.00,20,true,",
+	}, "\n")
+
+	h := NewHistory("", 10)
+	count, err := h.ImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportCSV returned error: %v", err)
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 imported entries, got %d", count)
+	}
+	if h.Count() != 3 {
+		t.Fatalf("Expected 3 entries in history, got %d", h.Count())
+	}
+
+	entries := h.GetAll()
+	if entries[0].Operation != "Addition" || entries[0].Result != 5 || !entries[0].Success {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Success || entries[1].Error != "division by zero" {
+		t.Errorf("Unexpected failed entry: %+v", entries[1])
+	}
+	if entries[2].Operation != "Multiplication" {
+		t.Errorf("Expected Multiplication, got %s", entries[2].Operation)
+	}
+}
+
+// TestImportCSVRespectsMaxSize tests that imports are trimmed to capacity.
+func TestImportCSVRespectsMaxSize(t *testing.T) {
+	input := strings.Join([]string{
+		"2024-01-02T10:00:00Z,Addition,1 + 1,2,true,",
+		"2024-01-02T10:01:00Z,Addition,2 + 2,4,true,",
+		"2024-01-02T10:02:00Z,Addition,3 + 3,6,true,",
+	}, "\n")
+
+	h := NewHistory("", 2)
+	count, err := h.ImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportCSV returned error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 imported rows, got %d", count)
+	}
+	if h.Count() != 2 {
+		t.Errorf("Expected history trimmed to 2, got %d", h.Count())
+	}
+}
+
+// TestExportImportCSVRoundTrip tests that exported CSV imports back unchanged.
+func TestExportImportCSVRoundTrip(t *testing.T) {
+	original := NewHistory("", 10)
+	original.AddSuccess("Addition", "0.10 + 0.20", 0.1+0.2)
+	original.AddError("Division", "1.00 / 0.00", nil)
+
+	var buf bytes.Buffer
+	if err := original.ExportCSV(&buf); err != nil {
+		t.Fatalf("ExportCSV returned error: %v", err)
+	}
+
+	imported := NewHistory("", 10)
+	if _, err := imported.ImportCSV(&buf); err != nil {
+		t.Fatalf("ImportCSV returned error: %v", err)
+	}
+
+	if imported.Count() != original.Count() {
+		t.Fatalf("Expected %d entries, got %d", original.Count(), imported.Count())
+	}
+	for i, entry := range imported.GetAll() {
+		want := original.Entries[i]
+		if entry.Operation != want.Operation || entry.Expression != want.Expression ||
+			entry.Result != want.Result || entry.Success != want.Success ||
+			!entry.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("Entry %d mismatch: expected %+v, got %+v", i, want, entry)
+		}
+	}
+}
+
+// TestExportCSVWithColumns tests exporting chosen columns in a custom order
+// and rejecting bad column lists before anything is written.
+func TestExportCSVWithColumns(t *testing.T) {
+	h := NewHistory("", 10)
+	h.Add(Entry{Operation: "Addition", Expression: "2 + 3", Result: 5, Success: true, Label: "rent, split"})
+	h.AddError("Division", "1 / 0", stderrors.New("division by zero"))
+
+	tests := []struct {
+		name     string
+		columns  []string
+		expected string
+		hasError bool
+	}{
+		{
+			name:     "subset in custom order",
+			columns:  []string{"result", "expression", "label"},
+			expected: "result,expression,label\n5,2 + 3,\"rent, split\"\n0,1 / 0,\n",
+		},
+		{
+			name:     "single column",
+			columns:  []string{"success"},
+			expected: "success\ntrue\nfalse\n",
+		},
+		{name: "unknown column", columns: []string{"result", "answer"}, hasError: true},
+		{name: "repeated column", columns: []string{"result", "result"}, hasError: true},
+		{name: "no columns", columns: nil, hasError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := h.ExportCSVWithColumns(&buf, tt.columns)
+			if tt.hasError {
+				var validationErr *errors.ValidationError
+				if !stderrors.As(err, &validationErr) {
+					t.Errorf("%s: expected ValidationError, got %v", tt.name, err)
+				}
+				if buf.Len() != 0 {
+					t.Errorf("%s: expected nothing written, got %q", tt.name, buf.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -3,9 +3,17 @@
 package history
 
 import (
+	"cli-calculator/internal/calculator"
 	"cli-calculator/internal/errors"
+	"cli-calculator/internal/logger"
+	"cli-calculator/internal/system"
 	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,44 +26,249 @@ type Entry struct {
 	Result    float64   `json:"result"`    // The result of the calculation
 	Success   bool      `json:"success"`   // Whether the calculation succeeded
 	Error     string    `json:"error,omitempty"` // Error message if failed
+	Duration  time.Duration `json:"duration_ns,omitempty"` // How long the calculation took (zero for older entries)
+	Label     string    `json:"label,omitempty"` // Optional user note (e.g., "tax estimate")
+	Count     int       `json:"count,omitempty"` // Times this calculation was repeated in a row (0 means once)
+}
+
+// Times returns how many calculations the entry stands for: Count when
+// consecutive duplicates were collapsed into it, otherwise 1.
+func (e Entry) Times() int {
+	if e.Count > 1 {
+		return e.Count
+	}
+	return 1
+}
+
+// sameCalculation reports whether two entries record the same calculation
+// and outcome, ignoring when it happened and how long it took.
+func sameCalculation(a, b Entry) bool {
+	return a.Operation == b.Operation && a.Expression == b.Expression &&
+		a.Result == b.Result && a.Success == b.Success && a.Error == b.Error && a.Label == b.Label
+}
+
+// EvictionPolicy decides which entries are dropped when history exceeds MaxSize.
+type EvictionPolicy int
+
+const (
+	EvictOldest    EvictionPolicy = iota // Drop the oldest entries first (default)
+	EvictLeastUsed                       // Drop entries of the least used operations first
+)
+
+// Clock provides the current time.
+// History uses it to stamp entries so tests can substitute a fixed time.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock backed by time.Now.
+type realClock struct{}
+
+// Now returns the current wall-clock time.
+func (realClock) Now() time.Time {
+	return time.Now()
 }
 
 // History manages a collection of calculation entries.
+// Its methods are safe for concurrent use; code that touches Entries or the
+// option fields directly must not run alongside them (use the setters below
+// once another goroutine may be saving).
 // This demonstrates slice usage and methods on structs.
 type History struct {
-	Entries  []Entry `json:"entries"`  // Slice of history entries
-	MaxSize  int     `json:"max_size"` // Maximum number of entries to keep
-	FilePath string  `json:"-"`        // Path to history file (not saved in JSON)
+	Entries        []Entry        `json:"entries"`  // Slice of history entries
+	MaxSize        int            `json:"max_size"` // Maximum number of entries to keep
+	FilePath       string         `json:"-"`        // Path to history file (not saved in JSON)
+	EvictionPolicy EvictionPolicy `json:"-"`        // How to trim when over capacity
+	ReadOnly       bool           `json:"-"`        // When true, Add, Clear, Merge, and Save change nothing
+	Dedup          bool           `json:"-"`        // When true, Add collapses consecutive identical entries
+	MaxBytes       int            `json:"-"`        // Largest file Save writes; oldest entries are dropped to fit (0 means no limit)
+
+	clock Clock        // Source of entry timestamps (unexported, so never serialized)
+	mu    sync.RWMutex // Guards Entries, stats, and dirty for the methods below
+	stats statsTracker // Running totals behind GetStatistics
+	dirty bool         // Entries changed since the last Load or Save
 }
 
 // NewHistory creates a new History instance with the given parameters.
 func NewHistory(filePath string, maxSize int) *History {
-	return &History{
+	h := &History{
 		Entries:  make([]Entry, 0, maxSize), // Pre-allocate slice capacity
 		MaxSize:  maxSize,
 		FilePath: filePath,
+		clock:    realClock{},
+	}
+	h.stats.rebuild(nil)
+	return h
+}
+
+// SetMaxSize changes the capacity and immediately trims entries beyond it
+// according to the eviction policy.
+func (h *History) SetMaxSize(maxSize int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.MaxSize = maxSize
+	before := len(h.Entries)
+	h.trim()
+	if len(h.Entries) != before {
+		h.dirty = true
+	}
+}
+
+// SetReadOnly turns read-only mode on or off.
+func (h *History) SetReadOnly(readOnly bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ReadOnly = readOnly
+}
+
+// SetDedup turns collapsing of consecutive identical entries on or off.
+func (h *History) SetDedup(dedup bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Dedup = dedup
+}
+
+// SetMaxBytes changes the largest file Save writes (0 means no limit).
+func (h *History) SetMaxBytes(maxBytes int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.MaxBytes = maxBytes
+}
+
+// Dirty reports whether entries have changed since the history was last
+// loaded or saved, i.e. whether exiting now without saving would lose them.
+func (h *History) Dirty() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.dirty
+}
+
+// SetClock replaces the clock used to timestamp new entries.
+func (h *History) SetClock(clock Clock) {
+	h.clock = clock
+}
+
+// now returns the current time from the configured clock.
+// A History built without NewHistory falls back to the real clock.
+func (h *History) now() time.Time {
+	if h.clock == nil {
+		return time.Now()
 	}
+	return h.clock.Now()
 }
 
-// Add adds a new entry to the history.
+// Add adds a new entry to the history. It does nothing when ReadOnly is set.
+// With Dedup set, an entry identical to the most recent one is counted on
+// that entry (which takes the newer timestamp) instead of being appended.
 // This demonstrates slice append and capacity management.
 func (h *History) Add(entry Entry) {
 	// Add timestamp if not set
 	if entry.Timestamp.IsZero() {
-		entry.Timestamp = time.Now()
+		entry.Timestamp = h.now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ReadOnly {
+		return
+	}
+	h.dirty = true
+
+	if h.Dedup && len(h.Entries) > 0 {
+		last := &h.Entries[len(h.Entries)-1]
+		if sameCalculation(*last, entry) {
+			h.stats.remove(*last)
+			last.Count = last.Times() + entry.Times()
+			last.Timestamp = entry.Timestamp
+			h.stats.add(*last)
+			return
+		}
 	}
 
 	// Append to slice
 	h.Entries = append(h.Entries, entry)
+	h.stats.add(entry)
+
+	// Trim if exceeds max size
+	h.trim()
+}
+
+// trim removes entries beyond MaxSize according to the eviction policy.
+// Callers must hold h.mu for writing.
+func (h *History) trim() {
+	excess := len(h.Entries) - h.MaxSize
+	if excess <= 0 {
+		return
+	}
 
-	// Trim if exceeds max size (keep most recent entries)
-	if len(h.Entries) > h.MaxSize {
-		// Remove oldest entries
-		excess := len(h.Entries) - h.MaxSize
+	switch h.EvictionPolicy {
+	case EvictLeastUsed:
+		h.evictLeastUsed(excess)
+	default:
+		// Remove oldest entries (keep most recent)
+		for i := 0; i < excess; i++ {
+			h.stats.remove(h.Entries[i])
+		}
 		h.Entries = h.Entries[excess:]
 	}
 }
 
+// evictLeastUsed removes count entries, always taking the oldest entry of the
+// operation with the fewest entries. The most recent entry is kept so a freshly
+// added calculation is not dropped straight away, unless every entry has to go
+// (MaxSize 0).
+func (h *History) evictLeastUsed(count int) {
+	evictable := len(h.Entries) - 1
+	if count >= len(h.Entries) {
+		evictable = len(h.Entries)
+	}
+
+	// One pass groups the evictable entries by operation, oldest first
+	operationCounts := make(map[string]int)
+	candidates := make(map[string][]int)
+	for i := range h.Entries {
+		op := h.Entries[i].Operation
+		operationCounts[op]++
+		if i < evictable {
+			candidates[op] = append(candidates[op], i)
+		}
+	}
+
+	removed := make(map[int]bool, count)
+	for ; count > 0; count-- {
+		// Take the operation with the lowest count, breaking ties by the
+		// oldest remaining entry
+		victim := ""
+		for op, indexes := range candidates {
+			if len(indexes) == 0 {
+				continue
+			}
+			if victim == "" || operationCounts[op] < operationCounts[victim] ||
+				(operationCounts[op] == operationCounts[victim] && indexes[0] < candidates[victim][0]) {
+				victim = op
+			}
+		}
+		if victim == "" {
+			break
+		}
+
+		removed[candidates[victim][0]] = true
+		candidates[victim] = candidates[victim][1:]
+		operationCounts[victim]--
+	}
+
+	kept := make([]Entry, 0, len(h.Entries)-len(removed))
+	for i, entry := range h.Entries {
+		if removed[i] {
+			h.stats.remove(entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	h.Entries = kept
+}
+
 // AddSuccess adds a successful calculation to history.
 func (h *History) AddSuccess(operation, expression string, result float64) {
 	h.Add(Entry{
@@ -83,42 +296,66 @@ func (h *History) AddError(operation, expression string, err error) {
 
 // GetRecent returns the most recent n entries.
 // This demonstrates slice slicing and bounds checking.
+// The returned slice is a copy, so later additions don't race with the caller.
 func (h *History) GetRecent(n int) []Entry {
 	if n <= 0 {
 		return []Entry{}
 	}
 
-	if n >= len(h.Entries) {
-		return h.Entries
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if n > len(h.Entries) {
+		n = len(h.Entries)
 	}
 
-	// Return last n entries
-	return h.Entries[len(h.Entries)-n:]
+	// Return a copy of the last n entries
+	recent := make([]Entry, n)
+	copy(recent, h.Entries[len(h.Entries)-n:])
+	return recent
 }
 
-// GetAll returns all history entries.
+// GetAll returns a copy of all history entries.
 func (h *History) GetAll() []Entry {
-	return h.Entries
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	all := make([]Entry, len(h.Entries))
+	copy(all, h.Entries)
+	return all
 }
 
 // Count returns the number of entries in history.
 func (h *History) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	return len(h.Entries)
 }
 
-// Clear removes all entries from history.
+// Clear removes all entries from history. It does nothing when ReadOnly is set.
 func (h *History) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ReadOnly {
+		return
+	}
 	h.Entries = make([]Entry, 0, h.MaxSize)
+	h.stats.rebuild(nil)
+	h.dirty = true
 }
 
 // Load loads history from the file.
 // This demonstrates file reading and JSON unmarshaling with error handling.
 func (h *History) Load() error {
 	// Check if file exists
-	if _, err := os.Stat(h.FilePath); os.IsNotExist(err) {
+	info, err := os.Stat(h.FilePath)
+	if os.IsNotExist(err) {
 		// File doesn't exist, start with empty history (not an error)
 		return nil
 	}
+	if err == nil && info.IsDir() {
+		return errors.NewFileError(h.FilePath, "read", errors.ErrIsDirectory)
+	}
 
 	// Read file
 	data, err := os.ReadFile(h.FilePath)
@@ -126,115 +363,166 @@ func (h *History) Load() error {
 		return errors.NewFileError(h.FilePath, "read", err)
 	}
 
-	// Unmarshal JSON
+	// Unmarshal JSON, salvaging what we can from a partly corrupt file
 	var loaded History
 	if err := json.Unmarshal(data, &loaded); err != nil {
-		return errors.WrapWithContext(err, "failed to parse history file")
+		entries, skipped := recoverEntries(data)
+		if len(entries) == 0 {
+			return errors.WrapWithContext(err, "failed to parse history file")
+		}
+
+		// The next save rewrites the file without the bad entries, so keep the original
+		backup := h.FilePath + ".corrupt"
+		if writeErr := os.WriteFile(backup, data, 0644); writeErr != nil {
+			logger.Warn("Failed to back up corrupt history file: %v", writeErr)
+			backup = "(no backup)"
+		}
+		logger.Warn("History file %s is corrupt: recovered %d entries, skipped %d (original kept at %s)",
+			h.FilePath, len(entries), skipped, backup)
+		loaded.Entries = entries
 	}
 
 	// Update entries (preserve FilePath and MaxSize)
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.Entries = loaded.Entries
+	h.stats.rebuild(h.Entries)
+	h.dirty = false
 
 	// Trim if loaded history exceeds current max size
-	if len(h.Entries) > h.MaxSize {
-		excess := len(h.Entries) - h.MaxSize
-		h.Entries = h.Entries[excess:]
+	h.trim()
+
+	return nil
+}
+
+// Validate checks the integrity of the history, e.g. after loading a file
+// that may have been edited by hand. It returns a ValidationError naming the
+// first bad entry, such as entries[3].operation.
+func (h *History) Validate() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.MaxSize < 0 {
+		return errors.NewValidationError("max_size", strconv.Itoa(h.MaxSize), "must not be negative")
+	}
+
+	for i := range h.Entries {
+		entry := &h.Entries[i]
+		field := func(name string) string { return fmt.Sprintf("entries[%d].%s", i, name) }
+
+		switch {
+		case entry.Timestamp.IsZero():
+			return errors.NewValidationError(field("timestamp"), "", "must be set")
+		case strings.TrimSpace(entry.Operation) == "":
+			return errors.NewValidationError(field("operation"), entry.Operation, "must not be empty")
+		case entry.Success && entry.Error != "":
+			return errors.NewValidationError(field("error"), entry.Error, "a successful entry cannot have an error")
+		case entry.Count < 0:
+			return errors.NewValidationError(field("count"), strconv.Itoa(entry.Count), "must not be negative")
+		case entry.Duration < 0:
+			return errors.NewValidationError(field("duration_ns"), strconv.FormatInt(int64(entry.Duration), 10), "must not be negative")
+		}
 	}
 
 	return nil
 }
 
 // Save saves history to the file.
+// A read-only history is never written, and Save returns nil so callers
+// such as auto-save don't report a failure the user asked for.
+// When MaxBytes is set, the oldest entries are dropped (from memory too)
+// until the file fits. A successful save clears Dirty.
 // This demonstrates JSON marshaling and file writing with error handling.
 func (h *History) Save() error {
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(h, "", "  ")
+	// Marshal to JSON with indentation; an Add after this point marks it dirty again
+	h.mu.Lock()
+	if h.ReadOnly {
+		h.mu.Unlock()
+		return nil
+	}
+	data, err := h.marshalWithinLimit()
+	wasDirty := h.dirty
+	h.dirty = false
+	h.mu.Unlock()
 	if err != nil {
+		h.restoreDirty(wasDirty)
 		return errors.WrapWithContext(err, "failed to marshal history")
 	}
 
-	// Write to file
-	if err := os.WriteFile(h.FilePath, data, 0644); err != nil {
+	// Write to file atomically (temp file + rename)
+	if err := system.WriteFileAtomic(h.FilePath, data, 0644); err != nil {
+		h.restoreDirty(wasDirty)
 		return errors.NewFileError(h.FilePath, "write", err)
 	}
 
 	return nil
 }
 
-// GetStatistics calculates statistics from history.
-// This demonstrates iteration, conditionals, and working with slices.
-type Statistics struct {
-	TotalCalculations   int
-	SuccessfulCount     int
-	FailedCount         int
-	MostUsedOperation   string
-	AverageResult       float64
-	FirstCalculation    *time.Time
-	LastCalculation     *time.Time
+// restoreDirty puts back the dirty flag after a failed save, keeping it set
+// if entries changed while the save was in progress.
+func (h *History) restoreDirty(wasDirty bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dirty = h.dirty || wasDirty
 }
 
-// GetStatistics returns statistics about the calculation history.
-func (h *History) GetStatistics() Statistics {
-	stats := Statistics{
-		TotalCalculations: len(h.Entries),
-	}
-
-	if len(h.Entries) == 0 {
-		return stats
+// marshalWithinLimit marshals the history, first dropping the fewest oldest
+// entries needed to bring the output within MaxBytes. If even an empty
+// history is too large, every entry is dropped.
+// Callers must hold h.mu for writing.
+func (h *History) marshalWithinLimit() ([]byte, error) {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil || h.MaxBytes <= 0 || len(data) <= h.MaxBytes {
+		return data, err
 	}
 
-	// Track operation counts
-	operationCounts := make(map[string]int)
-	var totalResult float64
-	var successfulResults int
-
-	// Iterate through entries
-	for i := range h.Entries {
-		entry := &h.Entries[i] // Use pointer to avoid copying
-
-		// Count success/failure
-		if entry.Success {
-			stats.SuccessfulCount++
-			totalResult += entry.Result
-			successfulResults++
-		} else {
-			stats.FailedCount++
-		}
-
-		// Count operations
-		operationCounts[entry.Operation]++
+	// The output only shrinks as more entries are dropped, so binary search
+	// for the cut instead of re-marshaling once per entry
+	all := h.Entries
+	drop := sort.Search(len(all), func(n int) bool {
+		h.Entries = all[n:]
+		candidate, err := json.MarshalIndent(h, "", "  ")
+		return err == nil && len(candidate) <= h.MaxBytes
+	})
 
-		// Track first and last calculation times
-		if stats.FirstCalculation == nil || entry.Timestamp.Before(*stats.FirstCalculation) {
-			t := entry.Timestamp
-			stats.FirstCalculation = &t
-		}
-		if stats.LastCalculation == nil || entry.Timestamp.After(*stats.LastCalculation) {
-			t := entry.Timestamp
-			stats.LastCalculation = &t
-		}
+	for _, entry := range all[:drop] {
+		h.stats.remove(entry)
 	}
+	h.Entries = all[drop:]
+	logger.Info("Dropped %d oldest history entries to keep %s within %d bytes", drop, h.FilePath, h.MaxBytes)
 
-	// Calculate average result
-	if successfulResults > 0 {
-		stats.AverageResult = totalResult / float64(successfulResults)
-	}
+	return json.MarshalIndent(h, "", "  ")
+}
 
-	// Find most used operation
-	maxCount := 0
-	for op, count := range operationCounts {
-		if count > maxCount {
-			maxCount = count
-			stats.MostUsedOperation = op
-		}
-	}
+// GetStatistics calculates statistics from history.
+// This demonstrates iteration, conditionals, and working with slices.
+type Statistics struct {
+	TotalCalculations   int        `json:"total_calculations"`
+	SuccessfulCount     int        `json:"successful_count"`
+	FailedCount         int        `json:"failed_count"`
+	MostUsedOperation   string     `json:"most_used_operation,omitempty"`
+	AverageResult       float64    `json:"average_result"`
+	FirstCalculation    *time.Time `json:"first_calculation,omitempty"`
+	LastCalculation     *time.Time `json:"last_calculation,omitempty"`
+}
 
-	return stats
+// GetStatistics returns statistics about the calculation history.
+// The totals are kept up to date as entries change, so the cost doesn't
+// grow with the size of the history.
+func (h *History) GetStatistics() Statistics {
+	// A write lock, since the tracker may rebuild or rescan timestamps
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.stats.statistics(h.Entries)
 }
 
 // Filter returns entries matching a predicate function.
 // This demonstrates function parameters and filtering.
 func (h *History) Filter(predicate func(Entry) bool) []Entry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	filtered := make([]Entry, 0)
 
 	for _, entry := range h.Entries {
@@ -259,3 +547,119 @@ func (h *History) GetFailed() []Entry {
 		return !e.Success
 	})
 }
+
+// ContainsSimilar reports whether history already holds a successful entry with
+// the same operation and expression whose result is within epsilon of e.Result.
+// Entries are scanned newest first since duplicates are usually recent.
+func (h *History) ContainsSimilar(e Entry, epsilon float64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for i := len(h.Entries) - 1; i >= 0; i-- {
+		entry := &h.Entries[i]
+		if !entry.Success || entry.Operation != e.Operation || entry.Expression != e.Expression {
+			continue
+		}
+		if calculator.AlmostEqual(entry.Result, e.Result, epsilon) {
+			return true
+		}
+	}
+	return false
+}
+
+// TopResults returns up to n successful entries with the largest results,
+// sorted in descending order. Failed entries are excluded.
+func (h *History) TopResults(n int) []Entry {
+	return h.rankedResults(n, func(a, b float64) bool { return a > b })
+}
+
+// BottomResults returns up to n successful entries with the smallest results,
+// sorted in ascending order. Failed entries are excluded.
+func (h *History) BottomResults(n int) []Entry {
+	return h.rankedResults(n, func(a, b float64) bool { return a < b })
+}
+
+// rankedResults sorts successful entries by result using less and keeps the first n.
+// GetSuccessful returns a fresh slice, so sorting it doesn't reorder history.
+func (h *History) rankedResults(n int, less func(a, b float64) bool) []Entry {
+	if n <= 0 {
+		return []Entry{}
+	}
+
+	entries := h.GetSuccessful()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return less(entries[i].Result, entries[j].Result)
+	})
+
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// entryKey identifies an entry by value so identical entries can be detected.
+// Timestamps are compared as instants, ignoring their location.
+type entryKey struct {
+	timestamp  int64
+	operation  string
+	expression string
+	result     float64
+	success    bool
+	err        string
+	label      string
+}
+
+// keyOf returns the identity key of an entry.
+// Duration is left out: timing the same calculation twice gives different
+// durations, but it is still the same entry.
+func keyOf(e Entry) entryKey {
+	return entryKey{
+		timestamp:  e.Timestamp.UnixNano(),
+		operation:  e.Operation,
+		expression: e.Expression,
+		result:     e.Result,
+		success:    e.Success,
+		err:        e.Error,
+		label:      e.Label,
+	}
+}
+
+// Merge appends the entries of src into dst, sorts them by timestamp,
+// removes identical duplicates, and trims dst to its MaxSize.
+// src is left unchanged, and so is dst when it is read-only.
+func Merge(dst *History, src *History) {
+	// Snapshot src before locking dst so merging a history into itself can't deadlock
+	srcEntries := src.GetAll()
+
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+	if dst.ReadOnly {
+		return
+	}
+
+	merged := make([]Entry, 0, len(dst.Entries)+len(srcEntries))
+	merged = append(merged, dst.Entries...)
+	merged = append(merged, srcEntries...)
+
+	// Stable sort keeps the original order of entries with equal timestamps
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+
+	// Drop entries identical to one already kept
+	seen := make(map[entryKey]bool, len(merged))
+	unique := merged[:0]
+	for _, entry := range merged {
+		key := keyOf(entry)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, entry)
+	}
+
+	dst.Entries = unique
+	dst.stats.rebuild(dst.Entries)
+	dst.trim()
+	dst.dirty = true
+}
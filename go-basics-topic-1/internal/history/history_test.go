@@ -0,0 +1,875 @@
+// Package history provides calculation history management with tests.
+// This demonstrates testing slice-based data structures.
+package history
+
+import (
+	"cli-calculator/internal/errors"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock struct {
+	t time.Time
+}
+
+// Now returns the fixed time.
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// TestSetClock tests that entries are stamped with the injected clock's time.
+func TestSetClock(t *testing.T) {
+	stamp := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+
+	h := NewHistory("", 10)
+	h.SetClock(fixedClock{stamp})
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddError("Division", "1 / 0", nil)
+
+	for i, entry := range h.GetAll() {
+		if !entry.Timestamp.Equal(stamp) {
+			t.Errorf("Entry %d: expected timestamp %v, got %v", i, stamp, entry.Timestamp)
+		}
+	}
+
+	// Explicit timestamps are kept as-is
+	explicit := stamp.Add(-time.Hour)
+	h.Add(Entry{Timestamp: explicit, Operation: "Power"})
+	if got := h.GetAll()[2].Timestamp; !got.Equal(explicit) {
+		t.Errorf("Expected explicit timestamp %v, got %v", explicit, got)
+	}
+}
+
+// TestContainsSimilar tests duplicate detection with a float tolerance.
+func TestContainsSimilar(t *testing.T) {
+	h := NewHistory("", 10)
+	h.AddSuccess("Addition", "0.10 + 0.20", 0.1+0.2)
+	h.AddError("Division", "1.00 / 0.00", nil)
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected bool
+	}{
+		{"same result within epsilon", Entry{Operation: "Addition", Expression: "0.10 + 0.20", Result: 0.3}, true},
+		{"different result", Entry{Operation: "Addition", Expression: "0.10 + 0.20", Result: 0.4}, false},
+		{"different expression", Entry{Operation: "Addition", Expression: "0.20 + 0.10", Result: 0.3}, false},
+		{"different operation", Entry{Operation: "Multiplication", Expression: "0.10 + 0.20", Result: 0.3}, false},
+		{"failed entries ignored", Entry{Operation: "Division", Expression: "1.00 / 0.00", Result: 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := h.ContainsSimilar(tt.entry, 1e-9)
+			if result != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// operations returns the operation names of entries in order.
+func operations(entries []Entry) []string {
+	ops := make([]string, len(entries))
+	for i, entry := range entries {
+		ops[i] = entry.Operation
+	}
+	return ops
+}
+
+// equalStrings reports whether two string slices hold the same values in order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestEvictionPolicyOnAdd tests which entries survive Add under each policy.
+func TestEvictionPolicyOnAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   EvictionPolicy
+		expected []string
+	}{
+		{"evict oldest", EvictOldest, []string{"Power", "Addition", "Addition", "Modulo"}},
+		{"evict least used", EvictLeastUsed, []string{"Addition", "Addition", "Addition", "Modulo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory("", 4)
+			h.EvictionPolicy = tt.policy
+			for _, op := range []string{"Addition", "Power", "Addition", "Addition", "Modulo"} {
+				h.AddSuccess(op, op, 1)
+			}
+
+			result := operations(h.GetAll())
+			if !equalStrings(result, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestEvictLeastUsedEvery tests that the least used policy can empty the
+// history, including the newest entry, when every entry must go.
+func TestEvictLeastUsedEvery(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxSize  int
+		expected []string
+	}{
+		{"max size 0", 0, []string{}},
+		{"keeps the newest", 1, []string{"Modulo"}},
+		{"keeps the most used", 2, []string{"Addition", "Modulo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory("", 10)
+			h.EvictionPolicy = EvictLeastUsed
+			for _, op := range []string{"Addition", "Power", "Addition", "Modulo"} {
+				h.AddSuccess(op, op, 1)
+			}
+
+			h.SetMaxSize(tt.maxSize)
+
+			result := operations(h.GetAll())
+			if !equalStrings(result, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+			if stats := h.GetStatistics(); stats.TotalCalculations != len(tt.expected) {
+				t.Errorf("%s: expected statistics for %d entries, got %d", tt.name, len(tt.expected), stats.TotalCalculations)
+			}
+		})
+	}
+
+	// Adding to a zero-capacity history keeps nothing
+	h := NewHistory("", 0)
+	h.EvictionPolicy = EvictLeastUsed
+	h.AddSuccess("Addition", "1 + 1", 2)
+	if h.Count() != 0 {
+		t.Errorf("Expected no entries with MaxSize 0, got %d", h.Count())
+	}
+}
+
+// TestEvictionPolicyOnLoad tests which entries survive loading an over-capacity file.
+func TestEvictionPolicyOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	// Save a history larger than the capacity used for loading
+	full := NewHistory(path, 10)
+	for _, op := range []string{"Division", "Addition", "Power", "Addition", "Power", "Addition"} {
+		full.AddSuccess(op, op, 1)
+	}
+	data, err := json.Marshal(full)
+	if err != nil {
+		t.Fatalf("Failed to marshal history: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write history file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   EvictionPolicy
+		expected []string
+	}{
+		{"evict oldest", EvictOldest, []string{"Addition", "Power", "Addition"}},
+		{"evict least used", EvictLeastUsed, []string{"Addition", "Addition", "Addition"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory(path, 3)
+			h.EvictionPolicy = tt.policy
+			if err := h.Load(); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			result := operations(h.GetAll())
+			if !equalStrings(result, tt.expected) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestMerge tests merging two histories with an overlapping duplicate entry.
+func TestMerge(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	shared := Entry{Timestamp: base.Add(2 * time.Minute), Operation: "Power", Expression: "2 ^ 3", Result: 8, Success: true}
+
+	dst := NewHistory("", 10)
+	dst.Add(Entry{Timestamp: base, Operation: "Addition", Expression: "1 + 1", Result: 2, Success: true})
+	dst.Add(shared)
+	dst.Add(Entry{Timestamp: base.Add(4 * time.Minute), Operation: "Modulo", Expression: "7 % 3", Result: 1, Success: true})
+
+	src := NewHistory("", 10)
+	src.Add(Entry{Timestamp: base.Add(1 * time.Minute), Operation: "Division", Expression: "6 / 3", Result: 2, Success: true})
+	src.Add(shared)
+	src.Add(Entry{Timestamp: base.Add(3 * time.Minute), Operation: "Subtraction", Expression: "5 - 1", Result: 4, Success: true})
+
+	Merge(dst, src)
+
+	expected := []string{"Addition", "Division", "Power", "Subtraction", "Modulo"}
+	result := operations(dst.GetAll())
+	if !equalStrings(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// The source history is left untouched
+	if src.Count() != 3 {
+		t.Errorf("Expected source to keep 3 entries, got %d", src.Count())
+	}
+}
+
+// TestMergeTrimsToMaxSize tests that merged history respects the destination capacity.
+func TestMergeTrimsToMaxSize(t *testing.T) {
+	base := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	dst := NewHistory("", 2)
+	dst.Add(Entry{Timestamp: base, Operation: "Addition", Success: true})
+
+	src := NewHistory("", 10)
+	src.Add(Entry{Timestamp: base.Add(time.Minute), Operation: "Division", Success: true})
+	src.Add(Entry{Timestamp: base.Add(2 * time.Minute), Operation: "Power", Success: true})
+
+	Merge(dst, src)
+
+	expected := []string{"Division", "Power"}
+	result := operations(dst.GetAll())
+	if !equalStrings(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// results returns the result values of entries in order.
+func results(entries []Entry) []float64 {
+	values := make([]float64, len(entries))
+	for i, entry := range entries {
+		values[i] = entry.Result
+	}
+	return values
+}
+
+// TestTopAndBottomResults tests result ranking in both directions.
+func TestTopAndBottomResults(t *testing.T) {
+	h := NewHistory("", 10)
+	h.AddSuccess("Addition", "a", 5)
+	h.AddSuccess("Subtraction", "b", -3)
+	h.AddError("Division", "c", nil)
+	h.AddSuccess("Power", "d", 100)
+	h.AddSuccess("Modulo", "e", 1)
+
+	tests := []struct {
+		name     string
+		entries  []Entry
+		expected []float64
+	}{
+		{"top 2", h.TopResults(2), []float64{100, 5}},
+		{"bottom 2", h.BottomResults(2), []float64{-3, 1}},
+		{"top more than available", h.TopResults(10), []float64{100, 5, 1, -3}},
+		{"bottom more than available", h.BottomResults(10), []float64{-3, 1, 5, 100}},
+		{"zero requested", h.TopResults(0), []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results(tt.entries)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, result)
+			}
+			for i := range tt.expected {
+				if result[i] != tt.expected[i] {
+					t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, result)
+					break
+				}
+			}
+		})
+	}
+
+	// Ranking must not reorder the history itself
+	if h.GetAll()[0].Result != 5 {
+		t.Error("TopResults reordered the underlying history")
+	}
+}
+
+// TestMostUsedOperationTieBreak tests that ties resolve alphabetically on every run.
+func TestMostUsedOperationTieBreak(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []string
+		expected   string
+	}{
+		{"two-way tie", []string{"Subtraction", "Addition", "Subtraction", "Addition"}, "Addition"},
+		{"tie with a loser", []string{"Power", "Modulo", "Division", "Power", "Modulo"}, "Modulo"},
+		{"clear winner", []string{"Addition", "Power", "Power"}, "Power"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory("", 10)
+			for _, op := range tt.operations {
+				h.AddSuccess(op, op, 1)
+			}
+
+			// Map iteration order varies, so repeat to catch nondeterminism
+			for i := 0; i < 50; i++ {
+				if got := h.GetStatistics().MostUsedOperation; got != tt.expected {
+					t.Fatalf("%s: expected %s, got %s", tt.name, tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadDuration tests loading entries written with and without durations.
+func TestLoadDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		blob     string
+		expected time.Duration
+	}{
+		{
+			"old entry without duration",
+			`{"entries":[{"timestamp":"2024-03-15T09:30:00Z","operation":"Addition","expression":"2 + 2","result":4,"success":true}],"max_size":10}`,
+			0,
+		},
+		{
+			"new entry with duration",
+			`{"entries":[{"timestamp":"2024-03-15T09:30:00Z","operation":"Addition","expression":"2 + 2","result":4,"success":true,"duration_ns":1500}],"max_size":10}`,
+			1500 * time.Nanosecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.json")
+			if err := os.WriteFile(path, []byte(tt.blob), 0644); err != nil {
+				t.Fatalf("%s: failed to write history: %v", tt.name, err)
+			}
+
+			h := NewHistory(path, 10)
+			if err := h.Load(); err != nil {
+				t.Fatalf("%s: Load failed: %v", tt.name, err)
+			}
+			if h.Count() != 1 {
+				t.Fatalf("%s: expected 1 entry, got %d", tt.name, h.Count())
+			}
+			if got := h.GetAll()[0].Duration; got != tt.expected {
+				t.Errorf("%s: expected duration %v, got %v", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestSaveDuration tests that durations are written as duration_ns and omitted when zero.
+func TestSaveDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h := NewHistory(path, 10)
+	h.Add(Entry{Operation: "Addition", Expression: "1 + 1", Result: 2, Success: true, Duration: 42 * time.Microsecond})
+	h.AddSuccess("Subtraction", "3 - 1", 2)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var raw struct {
+		Entries []map[string]interface{} `json:"entries"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if got := raw.Entries[0]["duration_ns"]; got != float64(42000) {
+		t.Errorf("Expected duration_ns 42000, got %v", got)
+	}
+	if _, ok := raw.Entries[1]["duration_ns"]; ok {
+		t.Error("Expected duration_ns to be omitted for an untimed entry")
+	}
+}
+
+// TestSaveMaxBytes tests that Save drops the oldest entries to fit the byte
+// cap, keeping the newest ones both on disk and in memory.
+func TestSaveMaxBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int
+		kept     int // Entries expected to survive; -1 means some but not all
+	}{
+		{"no limit", 0, 20},
+		{"roomy limit", 1 << 20, 20},
+		{"tight limit", 2000, -1},
+		{"smaller than an empty history", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.json")
+			h := NewHistory(path, 100)
+			h.MaxBytes = tt.maxBytes
+			for i := 0; i < 20; i++ {
+				h.Add(Entry{Operation: "Addition", Expression: fmt.Sprintf("%d + 1", i), Result: float64(i + 1), Success: true, Label: strings.Repeat("x", 200)})
+			}
+
+			if err := h.Save(); err != nil {
+				t.Fatalf("%s: Save failed: %v", tt.name, err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("%s: Stat failed: %v", tt.name, err)
+			}
+			if tt.maxBytes > 0 && tt.kept != 0 && info.Size() > int64(tt.maxBytes) {
+				t.Errorf("%s: expected file within %d bytes, got %d", tt.name, tt.maxBytes, info.Size())
+			}
+
+			saved := NewHistory(path, 100)
+			if err := saved.Load(); err != nil {
+				t.Fatalf("%s: Load failed: %v", tt.name, err)
+			}
+			kept := saved.Count()
+			if tt.kept >= 0 && kept != tt.kept {
+				t.Errorf("%s: expected %d entries saved, got %d", tt.name, tt.kept, kept)
+			}
+			if tt.kept < 0 && (kept == 0 || kept == 20) {
+				t.Errorf("%s: expected some but not all entries saved, got %d", tt.name, kept)
+			}
+			if h.Count() != kept || h.GetStatistics().TotalCalculations != kept {
+				t.Errorf("%s: expected memory to match the %d saved entries, got %d", tt.name, kept, h.Count())
+			}
+			if kept > 0 && saved.GetAll()[kept-1].Expression != "19 + 1" {
+				t.Errorf("%s: expected the newest entry kept, got %q", tt.name, saved.GetAll()[kept-1].Expression)
+			}
+		})
+	}
+}
+
+// TestLoadDirectory tests the clear error when the history path is a directory.
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	h := NewHistory(dir, 10)
+
+	err := h.Load()
+	if !stderrors.Is(err, errors.ErrIsDirectory) {
+		t.Fatalf("Expected ErrIsDirectory, got %v", err)
+	}
+
+	var fileErr *errors.FileError
+	if !stderrors.As(err, &fileErr) || fileErr.Path != dir {
+		t.Errorf("Expected a FileError for %s, got %v", dir, err)
+	}
+}
+
+// TestLoadRecoversCorruptEntries tests that a partly corrupt history file
+// loads its good entries instead of failing entirely.
+func TestLoadRecoversCorruptEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		blob     string
+		expected []string // Expressions expected to load
+		hasError bool
+	}{
+		{
+			"corrupt entry in saved format",
+			`{
+  "entries": [
+    {
+      "timestamp": "2024-03-15T09:30:00Z",
+      "operation": "Addition",
+      "expression": "2 + 2",
+      "result": 4,
+      "success": true
+    },
+    {
+      "timestamp": "2024-03-15T09:31:00Z",
+      "operation": "Division",
+      "result": 4x,
+      "success": true
+    },
+    {
+      "timestamp": "2024-03-15T09:32:00Z",
+      "operation": "Power",
+      "expression": "2 ^ 3",
+      "result": 8,
+      "success": true
+    }
+  ],
+  "max_size": 10
+}`,
+			[]string{"2 + 2", "2 ^ 3"},
+			false,
+		},
+		{
+			"corrupt lines in JSON Lines",
+			`{"timestamp":"2024-03-15T09:30:00Z","operation":"Addition","expression":"1 + 1","result":2,"success":true}
+{"timestamp":"2024-03-15T09:31:00Z","operation":"Subtr
+{"timestamp":"yesterday","operation":"Addition","expression":"5 + 5","result":10,"success":true}
+{"timestamp":"2024-03-15T09:33:00Z","operation":"Multiplication","expression":"3 * 4","result":12,"success":true}`,
+			[]string{"1 + 1", "3 * 4"},
+			false,
+		},
+		{
+			"file cut short",
+			`{"entries":[
+{"timestamp":"2024-03-15T09:30:00Z","operation":"Addition","expression":"2 + 2","result":4,"success":true},
+{"timestamp":"2024-03-15T09:31:00Z","operation":"Addi`,
+			[]string{"2 + 2"},
+			false,
+		},
+		{"nothing recoverable", `not json at all`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.json")
+			if err := os.WriteFile(path, []byte(tt.blob), 0644); err != nil {
+				t.Fatalf("%s: failed to write history: %v", tt.name, err)
+			}
+
+			h := NewHistory(path, 10)
+			err := h.Load()
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: Load failed: %v", tt.name, err)
+			}
+
+			entries := h.GetAll()
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("%s: expected %d entries, got %d: %+v", tt.name, len(tt.expected), len(entries), entries)
+			}
+			for i, want := range tt.expected {
+				if entries[i].Expression != want {
+					t.Errorf("%s: expected entry %d to be %q, got %q", tt.name, i, want, entries[i].Expression)
+				}
+			}
+
+			// The corrupt original is kept, since the next save drops the bad entries
+			if backup, err := os.ReadFile(path + ".corrupt"); err != nil || string(backup) != tt.blob {
+				t.Errorf("%s: expected the original file backed up, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// TestRecoverEntriesCountsSkipped tests the count of corrupt entries.
+func TestRecoverEntriesCountsSkipped(t *testing.T) {
+	blob := `{"entries": [
+{"timestamp":"2024-03-15T09:30:00Z","operation":"Addition","expression":"1 + 1","result":2,"success":true},
+{"timestamp":"2024-03-15T09:31:00Z","operation":"Addition","result":"two"},
+{"broken
+],"max_size":10}`
+
+	entries, skipped := recoverEntries([]byte(blob))
+	if len(entries) != 1 || skipped != 2 {
+		t.Errorf("Expected 1 entry and 2 skipped, got %d and %d", len(entries), skipped)
+	}
+}
+
+// TestConcurrentAdd tests that concurrent writers and readers don't race
+// (run with -race) and that no entry is lost.
+func TestConcurrentAdd(t *testing.T) {
+	const workers, perWorker = 8, 50
+	h := NewHistory(filepath.Join(t.TempDir(), "history.json"), workers*perWorker)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				h.AddSuccess("Addition", "1 + 1", 2)
+				_ = h.GetAll()
+				_ = h.GetStatistics()
+			}
+		}()
+	}
+
+	// Save concurrently as the SIGHUP handler would
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := h.Save(); err != nil {
+			t.Errorf("Save failed: %v", err)
+		}
+	}()
+
+	wg.Wait()
+
+	if got := h.Count(); got != workers*perWorker {
+		t.Errorf("Expected %d entries, got %d", workers*perWorker, got)
+	}
+}
+
+// TestReadOnly tests that a read-only history neither changes nor writes.
+func TestReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h := NewHistory(path, 10)
+	h.AddSuccess("Addition", "1 + 1", 2)
+	h.ReadOnly = true
+
+	h.AddSuccess("Addition", "2 + 2", 4)
+	if h.Count() != 1 {
+		t.Errorf("Expected Add to be ignored (1 entry), got %d", h.Count())
+	}
+
+	h.Clear()
+	if h.Count() != 1 {
+		t.Errorf("Expected Clear to be ignored (1 entry), got %d", h.Count())
+	}
+
+	src := NewHistory("", 10)
+	src.AddSuccess("Power", "2 ^ 3", 8)
+	Merge(h, src)
+	if h.Count() != 1 {
+		t.Errorf("Expected Merge to be ignored (1 entry), got %d", h.Count())
+	}
+
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected Save not to write %s, got stat error %v", path, err)
+	}
+}
+
+// TestDirty tests that changes mark history dirty until the next save or load.
+func TestDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h := NewHistory(path, 10)
+	if h.Dirty() {
+		t.Error("Expected a new history to be clean")
+	}
+
+	h.AddSuccess("Addition", "1 + 1", 2)
+	if !h.Dirty() {
+		t.Error("Expected Add to mark history dirty")
+	}
+
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if h.Dirty() {
+		t.Error("Expected Save to clear dirty")
+	}
+
+	h.Clear()
+	if !h.Dirty() {
+		t.Error("Expected Clear to mark history dirty")
+	}
+
+	if err := h.Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if h.Dirty() {
+		t.Error("Expected Load to clear dirty")
+	}
+
+	src := NewHistory("", 10)
+	src.AddSuccess("Power", "2 ^ 3", 8)
+	Merge(h, src)
+	if !h.Dirty() {
+		t.Error("Expected Merge to mark history dirty")
+	}
+
+	// A failed save leaves the changes marked unsaved
+	h.FilePath = t.TempDir()
+	if err := h.Save(); err == nil {
+		t.Fatal("Expected Save to a directory to fail")
+	}
+	if !h.Dirty() {
+		t.Error("Expected history still dirty after a failed save")
+	}
+
+	h.FilePath = path
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	h.ReadOnly = true
+	h.AddSuccess("Addition", "2 + 2", 4)
+	if h.Dirty() {
+		t.Error("Expected a read-only Add to leave history clean")
+	}
+}
+
+// TestEntryLabelJSON tests that labels round-trip and are omitted when empty.
+func TestEntryLabelJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+	}{
+		{"with label", "tax estimate"},
+		{"without label", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Entry{Operation: "Multiplication", Expression: "1200 * 0.25", Result: 300, Success: true, Label: tt.label}
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatalf("%s: Marshal failed: %v", tt.name, err)
+			}
+
+			var raw map[string]interface{}
+			if err := json.Unmarshal(data, &raw); err != nil {
+				t.Fatalf("%s: Unmarshal failed: %v", tt.name, err)
+			}
+			if _, ok := raw["label"]; ok != (tt.label != "") {
+				t.Errorf("%s: expected label key present=%v, got JSON %s", tt.name, tt.label != "", data)
+			}
+
+			var decoded Entry
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("%s: Unmarshal failed: %v", tt.name, err)
+			}
+			if decoded.Label != tt.label {
+				t.Errorf("%s: expected label %q, got %q", tt.name, tt.label, decoded.Label)
+			}
+		})
+	}
+}
+
+// TestDedupConsecutive tests that repeated calculations collapse into one
+// entry while non-adjacent repeats are kept apart.
+func TestDedupConsecutive(t *testing.T) {
+	h := NewHistory("", 10)
+	h.Dedup = true
+
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddSuccess("Multiplication", "2 * 3", 6)
+	h.AddSuccess("Addition", "2 + 2", 4)
+
+	entries := h.GetAll()
+	expected := []struct {
+		expression string
+		times      int
+	}{
+		{"2 + 2", 3},
+		{"2 * 3", 1},
+		{"2 + 2", 1},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, tt := range expected {
+		if entries[i].Expression != tt.expression || entries[i].Times() != tt.times {
+			t.Errorf("Entry %d: expected %q ×%d, got %q ×%d",
+				i, tt.expression, tt.times, entries[i].Expression, entries[i].Times())
+		}
+	}
+
+	if stats := h.GetStatistics(); stats.TotalCalculations != 5 {
+		t.Errorf("Expected statistics to count 5 calculations, got %d", stats.TotalCalculations)
+	}
+}
+
+// TestDedupDistinguishesOutcome tests that entries differing only in result
+// or success are not collapsed, and that dedup is off by default.
+func TestDedupDistinguishesOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		dedup  bool
+		second func(h *History)
+		count  int
+	}{
+		{"same entry, dedup off", false, func(h *History) { h.AddSuccess("Division", "1 / 0", 0) }, 2},
+		{"same entry, dedup on", true, func(h *History) { h.AddSuccess("Division", "1 / 0", 0) }, 1},
+		{"different result", true, func(h *History) { h.AddSuccess("Division", "1 / 0", 1) }, 2},
+		{"different success", true, func(h *History) { h.AddError("Division", "1 / 0", errors.ErrDivisionByZero) }, 2},
+	}
+
+	for _, tt := range tests {
+		h := NewHistory("", 10)
+		h.Dedup = tt.dedup
+		h.AddSuccess("Division", "1 / 0", 0)
+		tt.second(h)
+
+		if h.Count() != tt.count {
+			t.Errorf("%s: expected %d entries, got %d", tt.name, tt.count, h.Count())
+		}
+	}
+}
+
+// TestDedupCountRoundTrip tests that collapsed counts survive Save and Load.
+func TestDedupCountRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h := NewHistory(path, 10)
+	h.Dedup = true
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddSuccess("Addition", "2 + 2", 4)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded := NewHistory(path, 10)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	entries := loaded.GetAll()
+	if len(entries) != 1 || entries[0].Count != 2 {
+		t.Errorf("Expected one entry with count 2, got %+v", entries)
+	}
+}
+
+// TestValidate tests the integrity checks on history entries.
+func TestValidate(t *testing.T) {
+	stamp := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	valid := Entry{Timestamp: stamp, Operation: "Addition", Expression: "2 + 2", Result: 4, Success: true}
+
+	tests := []struct {
+		name     string
+		modify   func(e *Entry)
+		hasError bool
+	}{
+		{"valid entry", func(e *Entry) {}, false},
+		{"failed entry with error", func(e *Entry) { e.Success, e.Error = false, "division by zero" }, false},
+		{"missing timestamp", func(e *Entry) { e.Timestamp = time.Time{} }, true},
+		{"missing operation", func(e *Entry) { e.Operation = " " }, true},
+		{"successful entry with error", func(e *Entry) { e.Error = "division by zero" }, true},
+		{"negative count", func(e *Entry) { e.Count = -2 }, true},
+		{"negative duration", func(e *Entry) { e.Duration = -time.Second }, true},
+	}
+
+	for _, tt := range tests {
+		entry := valid
+		tt.modify(&entry)
+		// Set Entries directly, since Add would fill in a missing timestamp
+		h := NewHistory("", 10)
+		h.Entries = []Entry{valid, entry}
+
+		err := h.Validate()
+		if tt.hasError && err == nil {
+			t.Errorf("%s: expected error, got nil", tt.name)
+		}
+		if !tt.hasError && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
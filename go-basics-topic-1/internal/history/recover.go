@@ -0,0 +1,71 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// recoverEntries salvages the readable entries from history data that failed
+// to parse as a whole, such as a file cut short by a crash or edited by hand.
+// Each line starting with "{" is tried as the start of an entry, which covers
+// both the indented format Save writes and one entry per line (JSON Lines).
+// An entry that can't be decoded is counted in skipped and scanning resumes
+// on the next line, so one bad entry doesn't hide the ones after it.
+func recoverEntries(data []byte) (entries []Entry, skipped int) {
+	root := bytes.IndexByte(data, '{') // The document's own opening brace
+
+	for offset := 0; offset < len(data); {
+		next := len(data)
+		if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+			next = offset + i + 1
+		}
+
+		line := bytes.TrimSpace(data[offset:next])
+		if len(line) == 0 || line[0] != '{' {
+			offset = next
+			continue
+		}
+
+		start := offset + bytes.IndexByte(data[offset:next], '{')
+		if entry, end, ok := decodeEntryAt(data, start); ok {
+			entries = append(entries, entry)
+			offset = end
+			continue
+		}
+
+		// The top-level object isn't an entry, so it doesn't count as skipped
+		if start != root || !isHistoryObject(data[start:]) {
+			skipped++
+		}
+		offset = next
+	}
+
+	return entries, skipped
+}
+
+// decodeEntryAt decodes the JSON object starting at data[start] as an entry
+// and returns the offset just past it. Objects without a timestamp or
+// operation aren't entries, even if they are valid JSON.
+func decodeEntryAt(data []byte, start int) (Entry, int, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data[start:]))
+
+	var entry Entry
+	if err := decoder.Decode(&entry); err != nil {
+		return Entry{}, 0, false
+	}
+	if entry.Timestamp.IsZero() || entry.Operation == "" {
+		return Entry{}, 0, false
+	}
+	return entry, start + int(decoder.InputOffset()), true
+}
+
+// isHistoryObject reports whether data starts with the top-level history
+// object, judging by its first key, even if the rest of it is corrupt.
+func isHistoryObject(data []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil { // The opening brace
+		return false
+	}
+	key, err := decoder.Token()
+	return err == nil && (key == "entries" || key == "max_size")
+}
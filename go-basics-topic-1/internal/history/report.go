@@ -0,0 +1,84 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OperationCount is the number of entries recorded for one operation.
+type OperationCount struct {
+	Operation string
+	Count     int
+	Percent   float64 // Share of all entries, 0-100
+}
+
+// OperationBreakdown returns how many entries each operation has, sorted by
+// count (highest first) and then by name so the order is stable.
+// An empty history returns an empty breakdown.
+func (h *History) OperationBreakdown() []OperationCount {
+	h.mu.RLock()
+	total := 0
+	counts := make(map[string]int)
+	for i := range h.Entries {
+		times := h.Entries[i].Times()
+		counts[h.Entries[i].Operation] += times
+		total += times
+	}
+	h.mu.RUnlock()
+
+	// counts is empty when total is 0, so the division below never sees zero
+	breakdown := make([]OperationCount, 0, len(counts))
+	for op, count := range counts {
+		breakdown = append(breakdown, OperationCount{
+			Operation: op,
+			Count:     count,
+			Percent:   float64(count) / float64(total) * 100,
+		})
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Count != breakdown[j].Count {
+			return breakdown[i].Count > breakdown[j].Count
+		}
+		return breakdown[i].Operation < breakdown[j].Operation
+	})
+
+	return breakdown
+}
+
+// WriteStatisticsReport writes a human-readable statistics report to w.
+// This demonstrates io.Writer as a destination that can be a file, a buffer,
+// or standard output.
+func (h *History) WriteStatisticsReport(w io.Writer) error {
+	stats := h.GetStatistics()
+	divider := strings.Repeat("=", 40)
+
+	// Collect lines first so a single write error check covers the report
+	var b strings.Builder
+	fmt.Fprintln(&b, "CALCULATION STATISTICS REPORT")
+	fmt.Fprintf(&b, "Generated: %s\n", h.now().Format(time.RFC1123))
+	fmt.Fprintln(&b, divider)
+	fmt.Fprintf(&b, "Total calculations  : %d\n", stats.TotalCalculations)
+	fmt.Fprintf(&b, "Successful          : %d\n", stats.SuccessfulCount)
+	fmt.Fprintf(&b, "Failed              : %d\n", stats.FailedCount)
+
+	if stats.TotalCalculations > 0 {
+		fmt.Fprintf(&b, "Most used operation : %s\n", stats.MostUsedOperation)
+		fmt.Fprintf(&b, "Average result      : %g\n", stats.AverageResult)
+		fmt.Fprintf(&b, "First calculation   : %s\n", stats.FirstCalculation.Format(time.RFC1123))
+		fmt.Fprintf(&b, "Last calculation    : %s\n", stats.LastCalculation.Format(time.RFC1123))
+
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "OPERATION BREAKDOWN")
+		fmt.Fprintln(&b, divider)
+		for _, oc := range h.OperationBreakdown() {
+			fmt.Fprintf(&b, "%-20s: %d (%.1f%%)\n", oc.Operation, oc.Count, oc.Percent)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
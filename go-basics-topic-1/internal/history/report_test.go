@@ -0,0 +1,101 @@
+// Package history provides statistics reporting with tests.
+package history
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestOperationBreakdown tests per-operation counts and their ordering.
+func TestOperationBreakdown(t *testing.T) {
+	h := NewHistory("", 10)
+	h.AddSuccess("Division", "a", 1)
+	h.AddSuccess("Addition", "b", 1)
+	h.AddSuccess("Addition", "c", 1)
+	h.AddSuccess("Power", "d", 1)
+
+	breakdown := h.OperationBreakdown()
+	expected := []OperationCount{{"Addition", 2, 50}, {"Division", 1, 25}, {"Power", 1, 25}}
+
+	if len(breakdown) != len(expected) {
+		t.Fatalf("Expected %d operations, got %d", len(expected), len(breakdown))
+	}
+	for i := range expected {
+		if breakdown[i] != expected[i] {
+			t.Errorf("Position %d: expected %+v, got %+v", i, expected[i], breakdown[i])
+		}
+	}
+}
+
+// TestOperationBreakdownPercentages tests that shares add up to 100 percent.
+func TestOperationBreakdownPercentages(t *testing.T) {
+	h := NewHistory("", 10)
+	for _, op := range []string{"Addition", "Addition", "Division", "Power", "Power", "Power", "Modulo"} {
+		h.AddSuccess(op, "x", 1)
+	}
+
+	sum := 0.0
+	for _, oc := range h.OperationBreakdown() {
+		sum += oc.Percent
+	}
+	if math.Abs(sum-100) > 1e-9 {
+		t.Errorf("Expected percentages to sum to 100, got %v", sum)
+	}
+}
+
+// TestOperationBreakdownEmpty tests that an empty history has no breakdown.
+func TestOperationBreakdownEmpty(t *testing.T) {
+	h := NewHistory("", 10)
+
+	if breakdown := h.OperationBreakdown(); len(breakdown) != 0 {
+		t.Errorf("Expected empty breakdown, got %+v", breakdown)
+	}
+}
+
+// TestWriteStatisticsReport tests the contents of the statistics report.
+func TestWriteStatisticsReport(t *testing.T) {
+	h := NewHistory("", 10)
+	h.AddSuccess("Addition", "2 + 2", 4)
+	h.AddSuccess("Addition", "3 + 3", 6)
+	h.AddError("Division", "1 / 0", nil)
+
+	var buf bytes.Buffer
+	if err := h.WriteStatisticsReport(&buf); err != nil {
+		t.Fatalf("WriteStatisticsReport returned error: %v", err)
+	}
+	report := buf.String()
+
+	expectedLines := []string{
+		"Total calculations  : 3",
+		"Successful          : 2",
+		"Failed              : 1",
+		"Most used operation : Addition",
+		"Addition            : 2 (66.7%)",
+		"Division            : 1 (33.3%)",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(report, line) {
+			t.Errorf("Expected report to contain %q, got:\n%s", line, report)
+		}
+	}
+}
+
+// TestWriteStatisticsReportEmpty tests the report for an empty history.
+func TestWriteStatisticsReportEmpty(t *testing.T) {
+	h := NewHistory("", 10)
+
+	var buf bytes.Buffer
+	if err := h.WriteStatisticsReport(&buf); err != nil {
+		t.Fatalf("WriteStatisticsReport returned error: %v", err)
+	}
+
+	report := buf.String()
+	if !strings.Contains(report, "Total calculations  : 0") {
+		t.Errorf("Expected zero total, got:\n%s", report)
+	}
+	if strings.Contains(report, "OPERATION BREAKDOWN") {
+		t.Errorf("Expected no breakdown for empty history, got:\n%s", report)
+	}
+}
@@ -0,0 +1,150 @@
+package history
+
+import "time"
+
+// statsTracker keeps running totals so GetStatistics doesn't rescan every
+// entry on each call. History updates it whenever Entries change; a tracker
+// that is not valid (e.g. in a History built without NewHistory) is rebuilt
+// from the entries on first use.
+type statsTracker struct {
+	valid      bool           // Totals match Entries
+	entries    int            // Number of entries, as opposed to calculations
+	total      int            // Calculations, counting collapsed duplicates
+	successful int            // Successful calculations
+	failed     int            // Failed calculations
+	resultSum  float64        // Sum of successful results, for the average
+	opCounts   map[string]int // Calculations per operation
+	first      time.Time      // Earliest entry timestamp
+	last       time.Time      // Latest entry timestamp
+	timesStale bool           // An entry at first or last was removed; rescan timestamps
+	ordered    bool           // Entries are in timestamp order, so first and last are at the ends
+	tail       time.Time      // Timestamp of the most recently added entry
+}
+
+// rebuild recomputes the totals from entries.
+func (s *statsTracker) rebuild(entries []Entry) {
+	*s = statsTracker{valid: true, ordered: true, opCounts: make(map[string]int)}
+	for i := range entries {
+		s.add(entries[i])
+	}
+}
+
+// add counts a new entry. It does nothing while the tracker is invalid,
+// since the next rebuild will include the entry anyway.
+func (s *statsTracker) add(e Entry) {
+	if !s.valid {
+		return
+	}
+
+	times := e.Times() // Collapsed duplicates count once per repeat
+	s.entries++
+	s.total += times
+	if e.Success {
+		s.successful += times
+		s.resultSum += e.Result * float64(times)
+	} else {
+		s.failed += times
+	}
+	s.opCounts[e.Operation] += times
+
+	// An out-of-order entry means first and last must be tracked explicitly
+	if s.ordered && s.entries > 1 && e.Timestamp.Before(s.tail) {
+		s.ordered = false
+		s.timesStale = true // first may belong to an entry removed while ordered
+	}
+	s.tail = e.Timestamp
+
+	if s.timesStale {
+		return // The rescan will see this entry too
+	}
+	if s.entries == 1 || e.Timestamp.Before(s.first) {
+		s.first = e.Timestamp
+	}
+	if s.entries == 1 || e.Timestamp.After(s.last) {
+		s.last = e.Timestamp
+	}
+}
+
+// remove uncounts an entry that left the history. Removing the earliest or
+// latest entry marks the timestamps stale, because the next earliest or
+// latest can only be found by looking at the remaining entries (cheaply,
+// at the ends, while they are in timestamp order).
+func (s *statsTracker) remove(e Entry) {
+	if !s.valid {
+		return
+	}
+
+	times := e.Times()
+	s.entries--
+	s.total -= times
+	if e.Success {
+		s.successful -= times
+		s.resultSum -= e.Result * float64(times)
+	} else {
+		s.failed -= times
+	}
+	if s.opCounts[e.Operation] -= times; s.opCounts[e.Operation] <= 0 {
+		delete(s.opCounts, e.Operation)
+	}
+
+	if s.entries == 0 {
+		// Nothing left: start over so float error can't linger in the sum
+		s.rebuild(nil)
+		return
+	}
+	if e.Timestamp.Equal(s.first) || e.Timestamp.Equal(s.last) {
+		s.timesStale = true
+	}
+}
+
+// statistics builds a Statistics value from the totals. Only the timestamps
+// may need a pass over entries, and only after a boundary entry was removed
+// from entries that are out of timestamp order.
+func (s *statsTracker) statistics(entries []Entry) Statistics {
+	if !s.valid {
+		s.rebuild(entries)
+	}
+
+	var stats Statistics
+	if s.entries == 0 {
+		return stats
+	}
+
+	stats.TotalCalculations = s.total
+	stats.SuccessfulCount = s.successful
+	stats.FailedCount = s.failed
+	if s.successful > 0 {
+		stats.AverageResult = s.resultSum / float64(s.successful)
+	}
+
+	// Find most used operation; ties go to the alphabetically first name
+	// so the result doesn't depend on map iteration order
+	maxCount := 0
+	for op, count := range s.opCounts {
+		if count > maxCount || (count == maxCount && op < stats.MostUsedOperation) {
+			maxCount = count
+			stats.MostUsedOperation = op
+		}
+	}
+
+	if s.ordered {
+		s.first, s.last = entries[0].Timestamp, entries[len(entries)-1].Timestamp
+		s.timesStale = false
+	} else if s.timesStale {
+		s.first, s.last = entries[0].Timestamp, entries[0].Timestamp
+		for i := range entries {
+			if entries[i].Timestamp.Before(s.first) {
+				s.first = entries[i].Timestamp
+			}
+			if entries[i].Timestamp.After(s.last) {
+				s.last = entries[i].Timestamp
+			}
+		}
+		s.timesStale = false
+	}
+	first, last := s.first, s.last
+	stats.FirstCalculation = &first
+	stats.LastCalculation = &last
+
+	return stats
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"cli-calculator/internal/errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// scanStatistics is the previous full-scan GetStatistics, kept as the
+// reference the incremental tracker must agree with.
+func scanStatistics(entries []Entry) Statistics {
+	var stats Statistics
+	if len(entries) == 0 {
+		return stats
+	}
+
+	operationCounts := make(map[string]int)
+	var totalResult float64
+	var successfulResults int
+
+	for i := range entries {
+		entry := &entries[i]
+		times := entry.Times()
+		stats.TotalCalculations += times
+
+		if entry.Success {
+			stats.SuccessfulCount += times
+			totalResult += entry.Result * float64(times)
+			successfulResults += times
+		} else {
+			stats.FailedCount += times
+		}
+
+		operationCounts[entry.Operation] += times
+
+		if stats.FirstCalculation == nil || entry.Timestamp.Before(*stats.FirstCalculation) {
+			t := entry.Timestamp
+			stats.FirstCalculation = &t
+		}
+		if stats.LastCalculation == nil || entry.Timestamp.After(*stats.LastCalculation) {
+			t := entry.Timestamp
+			stats.LastCalculation = &t
+		}
+	}
+
+	if successfulResults > 0 {
+		stats.AverageResult = totalResult / float64(successfulResults)
+	}
+
+	maxCount := 0
+	for op, count := range operationCounts {
+		if count > maxCount || (count == maxCount && op < stats.MostUsedOperation) {
+			maxCount = count
+			stats.MostUsedOperation = op
+		}
+	}
+
+	return stats
+}
+
+// checkStatistics compares GetStatistics with a full scan of the entries.
+// Averages may differ in the last bits once results have been subtracted.
+func checkStatistics(t *testing.T, step string, h *History) {
+	t.Helper()
+	got := h.GetStatistics()
+	want := scanStatistics(h.GetAll())
+
+	if got.TotalCalculations != want.TotalCalculations || got.SuccessfulCount != want.SuccessfulCount ||
+		got.FailedCount != want.FailedCount || got.MostUsedOperation != want.MostUsedOperation {
+		t.Errorf("%s: expected %+v, got %+v", step, want, got)
+	}
+	if math.Abs(got.AverageResult-want.AverageResult) > 1e-9 {
+		t.Errorf("%s: expected average %v, got %v", step, want.AverageResult, got.AverageResult)
+	}
+	if !sameTime(got.FirstCalculation, want.FirstCalculation) || !sameTime(got.LastCalculation, want.LastCalculation) {
+		t.Errorf("%s: expected times %v-%v, got %v-%v",
+			step, want.FirstCalculation, want.LastCalculation, got.FirstCalculation, got.LastCalculation)
+	}
+}
+
+// sameTime reports whether two optional times are both unset or equal.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// TestIncrementalStatisticsMatchFullScan tests that the running totals agree
+// with a full scan after every kind of change to the history.
+func TestIncrementalStatisticsMatchFullScan(t *testing.T) {
+	for _, policy := range []EvictionPolicy{EvictOldest, EvictLeastUsed} {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		h := NewHistory(filepath.Join(t.TempDir(), "history.json"), 5)
+		h.EvictionPolicy = policy
+		name := fmt.Sprintf("policy %d", policy)
+
+		checkStatistics(t, name+", empty", h)
+
+		operations := []string{"Addition", "Division", "Addition", "Power", "Modulo", "Addition", "Division"}
+		for i, op := range operations {
+			now = now.Add(time.Minute)
+			h.SetClock(fixedClock{now})
+			if i%3 == 2 {
+				h.AddError(op, "x / 0", errors.ErrDivisionByZero)
+			} else {
+				h.AddSuccess(op, fmt.Sprintf("step %d", i), float64(i)*1.1)
+			}
+			checkStatistics(t, fmt.Sprintf("%s, add %d (trimmed)", name, i), h)
+		}
+
+		// An entry timestamped before all others becomes the first calculation
+		h.Add(Entry{Operation: "Power", Expression: "2 ^ 2", Result: 4, Success: true, Timestamp: now.Add(-time.Hour)})
+		checkStatistics(t, name+", out-of-order add", h)
+
+		h.Dedup = true
+		h.SetClock(fixedClock{now.Add(time.Minute)})
+		h.AddSuccess("Power", "2 ^ 2", 4)
+		checkStatistics(t, name+", dedup", h)
+
+		h.SetMaxSize(2)
+		checkStatistics(t, name+", shrink", h)
+
+		if err := h.Save(); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+		h.Clear()
+		checkStatistics(t, name+", clear", h)
+
+		if err := h.Load(); err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		checkStatistics(t, name+", load", h)
+
+		other := NewHistory("", 10)
+		other.AddSuccess("Multiplication", "3 * 3", 9)
+		Merge(h, other)
+		checkStatistics(t, name+", merge", h)
+	}
+}
+
+// TestStatisticsZeroValueHistory tests that a History built without
+// NewHistory still reports correct statistics.
+func TestStatisticsZeroValueHistory(t *testing.T) {
+	h := &History{MaxSize: 10}
+	h.AddSuccess("Addition", "1 + 1", 2)
+	h.AddSuccess("Addition", "2 + 2", 4)
+
+	if stats := h.GetStatistics(); stats.TotalCalculations != 2 || stats.AverageResult != 3 {
+		t.Errorf("Expected 2 calculations averaging 3, got %+v", stats)
+	}
+}
+
+// newLargeHistory returns a full history of n entries for benchmarks.
+func newLargeHistory(n int) *History {
+	h := NewHistory("", n)
+	operations := []string{"Addition", "Subtraction", "Multiplication", "Division", "Power"}
+	for i := 0; i < n; i++ {
+		h.AddSuccess(operations[i%len(operations)], "x", float64(i))
+	}
+	return h
+}
+
+// BenchmarkGetStatistics benchmarks statistics for a 10,000-entry history
+// using the incremental totals.
+func BenchmarkGetStatistics(b *testing.B) {
+	h := newLargeHistory(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.GetStatistics()
+	}
+}
+
+// BenchmarkGetStatisticsFullScan benchmarks the previous approach of scanning
+// every entry on each call, for comparison with BenchmarkGetStatistics.
+func BenchmarkGetStatisticsFullScan(b *testing.B) {
+	h := newLargeHistory(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanStatistics(h.Entries)
+	}
+}
+
+// BenchmarkAddAndGetStatistics benchmarks a full history that evicts an
+// entry on every Add, the worst case for the running totals.
+func BenchmarkAddAndGetStatistics(b *testing.B) {
+	h := newLargeHistory(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.AddSuccess("Addition", "x", float64(i))
+		h.GetStatistics()
+	}
+}
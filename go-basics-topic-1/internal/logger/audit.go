@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"cli-calculator/internal/errors"
+	"encoding/json"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one line of the audit log: who ran which calculation, when,
+// and how it turned out.
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	Operation  string    `json:"operation"`
+	Expression string    `json:"expression"`
+	Result     float64   `json:"result"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// AuditLog appends a JSON line per calculation to a file. Unlike history it
+// is never trimmed, cleared, or rewritten: the file is opened in append-only
+// mode and each record is a single write, so earlier lines are left intact.
+// A nil *AuditLog records nothing, so callers needn't check whether auditing
+// is configured.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+	user string // Resolved once, since it can't change during a run
+}
+
+// OpenAuditLog opens (creating if needed) the audit log at path for appending.
+// The file is readable only by its owner, as it records who did what.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.NewFileError(path, "open", err)
+	}
+	return &AuditLog{file: file, path: path, user: currentUser()}, nil
+}
+
+// currentUser names the user running the calculator, falling back to $USER
+// (or $USERNAME on Windows) when the account can't be looked up.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, name := range []string{"USER", "USERNAME"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return "unknown"
+}
+
+// Record appends one calculation. calcErr is the calculation's error, if it
+// failed; the returned error reports a failure to write the record.
+func (a *AuditLog) Record(operation, expression string, result float64, calcErr error) error {
+	if a == nil {
+		return nil
+	}
+
+	record := AuditRecord{
+		Time:       time.Now(),
+		User:       a.user,
+		Operation:  operation,
+		Expression: expression,
+		Result:     result,
+		Success:    calcErr == nil,
+	}
+	if calcErr != nil {
+		record.Result = 0
+		record.Error = calcErr.Error()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.WrapWithContext(err, "failed to marshal audit record")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return errors.NewFileError(a.path, "append", err)
+	}
+	return nil
+}
+
+// Close closes the audit log file.
+func (a *AuditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	return a.file.Close()
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readAuditRecords parses every line of the audit log at path.
+func readAuditRecords(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Audit line %q is not JSON: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestAuditLogRecord tests that records are appended across reopenings.
+func TestAuditLogRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	audit, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("OpenAuditLog failed: %v", err)
+	}
+	if err := audit.Record("Addition", "2 + 3", 5, nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	audit.Close()
+
+	// Reopening must append rather than truncate
+	audit, err = OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("OpenAuditLog failed: %v", err)
+	}
+	if err := audit.Record("Division", "1 / 0", 0, stderrors.New("division by zero")); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	audit.Close()
+
+	records := readAuditRecords(t, path)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if r := records[0]; r.Expression != "2 + 3" || r.Result != 5 || !r.Success || r.User == "" || r.Time.IsZero() {
+		t.Errorf("Unexpected success record: %+v", r)
+	}
+	if r := records[1]; r.Success || r.Error != "division by zero" {
+		t.Errorf("Unexpected failure record: %+v", r)
+	}
+}
+
+// TestAuditLogNil tests that a nil audit log (auditing off) is a no-op.
+func TestAuditLogNil(t *testing.T) {
+	var audit *AuditLog
+	if err := audit.Record("Addition", "1 + 1", 2, nil); err != nil {
+		t.Errorf("Expected nil audit log to record nothing, got %v", err)
+	}
+	if err := audit.Close(); err != nil {
+		t.Errorf("Expected nil audit log to close cleanly, got %v", err)
+	}
+}
+
+// TestOpenAuditLogBadPath tests that an unopenable path is reported.
+func TestOpenAuditLogBadPath(t *testing.T) {
+	if _, err := OpenAuditLog(filepath.Join(t.TempDir(), "missing", "audit.log")); err == nil {
+		t.Error("Expected error for a path in a missing directory, got nil")
+	}
+}
@@ -4,6 +4,7 @@ package logger
 
 import (
 	"cli-calculator/internal/constants"
+	"cli-calculator/internal/system"
 	"fmt"
 	"io"
 	"os"
@@ -24,6 +25,36 @@ type LogConfig struct {
 	TimeFormat string             // Time format for timestamps
 	Prefix     string             // Optional prefix for log messages
 	Enabled    bool               // Whether logging is enabled
+	Colorize   bool               // Color-code the level when output is a terminal
+}
+
+// isTerminal decides whether colors are shown.
+// It is a variable so tests can simulate a terminal.
+var isTerminal = system.IsTerminal
+
+// ANSI escape codes for log level colors
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorCyan   = "\033[36m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+// levelColor returns the ANSI color for a log level.
+func levelColor(level constants.LogLevel) string {
+	switch level {
+	case constants.LogLevelDebug:
+		return colorGray
+	case constants.LogLevelInfo:
+		return colorCyan
+	case constants.LogLevelWarn:
+		return colorYellow
+	case constants.LogLevelError:
+		return colorRed
+	default:
+		return ""
+	}
 }
 
 // Global logger instance (package-level variable)
@@ -45,6 +76,7 @@ func NewLogger(config *LogConfig) *Logger {
 			TimeFormat: "2006-01-02 15:04:05",
 			Prefix:     constants.AppName,
 			Enabled:    true,
+			Colorize:   true,
 		}
 	}
 
@@ -64,6 +96,12 @@ func (l *Logger) SetOutput(w io.Writer) {
 	l.output = w
 }
 
+// SetColorize turns color-coded levels on or off.
+// Colors are only ever written when the output is a terminal.
+func (l *Logger) SetColorize(colorize bool) {
+	l.config.Colorize = colorize
+}
+
 // Enable enables or disables logging.
 func (l *Logger) Enable(enabled bool) {
 	l.config.Enabled = enabled
@@ -82,11 +120,17 @@ func (l *Logger) log(level constants.LogLevel, format string, args ...interface{
 	// Format the message
 	message := fmt.Sprintf(format, args...)
 
+	// Color the level only for terminals so files and pipes stay plain
+	levelText := level.String()
+	if l.config.Colorize && isTerminal(l.output) {
+		levelText = levelColor(level) + levelText + colorReset
+	}
+
 	// Build the log line
 	logLine := fmt.Sprintf("[%s] [%s] [%s] %s\n",
 		timestamp,
 		l.config.Prefix,
-		level.String(),
+		levelText,
 		message,
 	)
 
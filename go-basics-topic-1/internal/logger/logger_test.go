@@ -0,0 +1,61 @@
+// Package logger provides structured logging with tests.
+package logger
+
+import (
+	"bytes"
+	"cli-calculator/internal/constants"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestColorize tests that levels are colored only for terminal output.
+func TestColorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		colorize bool
+		terminal bool
+		level    constants.LogLevel
+		expected string
+	}{
+		{"warn on terminal", true, true, constants.LogLevelWarn, "[" + colorYellow + "WARN" + colorReset + "]"},
+		{"error on terminal", true, true, constants.LogLevelError, "[" + colorRed + "ERROR" + colorReset + "]"},
+		{"buffer suppresses colors", true, false, constants.LogLevelWarn, "[WARN]"},
+		{"colorize off", false, true, constants.LogLevelWarn, "[WARN]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := isTerminal
+			isTerminal = func(io.Writer) bool { return tt.terminal }
+			defer func() { isTerminal = previous }()
+
+			var buf bytes.Buffer
+			l := NewLogger(nil)
+			l.SetOutput(&buf)
+			l.SetColorize(tt.colorize)
+			l.log(tt.level, "disk nearly full")
+
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("%s: expected %q in %q", tt.name, tt.expected, buf.String())
+			}
+			if !tt.terminal || !tt.colorize {
+				if strings.Contains(buf.String(), "\033[") {
+					t.Errorf("%s: expected no escape codes, got %q", tt.name, buf.String())
+				}
+			}
+		})
+	}
+}
+
+// TestColorizeRealBuffer tests that a plain buffer is never treated as a terminal.
+func TestColorizeRealBuffer(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(nil)
+	l.SetOutput(&buf)
+	l.Info("hello")
+
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no escape codes for a buffer, got %q", buf.String())
+	}
+}
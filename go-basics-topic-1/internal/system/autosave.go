@@ -0,0 +1,31 @@
+package system
+
+import "time"
+
+// SaveOnTick saves p each time ticks delivers a value and passes the outcome
+// of each save to onSave (nil on success). The returned stop function halts
+// the goroutine, waits for it to finish, and saves one final time so nothing
+// recorded since the last tick is lost.
+// Pass a time.Ticker's channel in production and a plain channel in tests.
+func SaveOnTick(p Persister, ticks <-chan time.Time, onSave func(error)) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case <-ticks:
+				onSave(p.Save())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+		onSave(p.Save())
+	}
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSaveOnTick tests that each tick saves once and stop saves a final time.
+func TestSaveOnTick(t *testing.T) {
+	p := &fakePersister{}
+	ticks := make(chan time.Time)
+	saved := make(chan error, 1)
+
+	stop := SaveOnTick(p, ticks, func(err error) { saved <- err })
+
+	for i := 1; i <= 3; i++ {
+		ticks <- time.Now()
+		select {
+		case <-saved:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Tick %d: timed out waiting for save", i)
+		}
+		if p.saves != i {
+			t.Errorf("Tick %d: expected %d saves, got %d", i, i, p.saves)
+		}
+	}
+
+	stop()
+	<-saved
+	if p.saves != 4 {
+		t.Errorf("Expected a final save on stop (4 total), got %d", p.saves)
+	}
+}
+
+// TestSaveOnTickWithTicker tests a real short interval end to end.
+func TestSaveOnTickWithTicker(t *testing.T) {
+	p := &fakePersister{}
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	saved := make(chan error, 10)
+
+	stop := SaveOnTick(p, ticker.C, func(err error) { saved <- err })
+
+	select {
+	case <-saved:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an interval save")
+	}
+	stop()
+
+	if p.saves < 2 {
+		t.Errorf("Expected an interval save and a final save, got %d", p.saves)
+	}
+}
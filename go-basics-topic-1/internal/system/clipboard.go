@@ -0,0 +1,64 @@
+package system
+
+import (
+	"cli-calculator/internal/errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardTools lists the copy commands to try for each GOOS, in order of
+// preference. Each command reads the text to copy from stdin.
+var clipboardTools = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+}
+
+// unixClipboardTools covers Linux and the BSDs under X11 and Wayland.
+var unixClipboardTools = [][]string{
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"wl-copy"},
+}
+
+// lookPath finds clipboard tools; it is a variable so tests can stub it.
+var lookPath = exec.LookPath
+
+// clipboardCommand returns the first available copy command for goos.
+// It returns errors.ErrNoClipboard when none of the tools is installed.
+func clipboardCommand(goos string) ([]string, error) {
+	candidates, ok := clipboardTools[goos]
+	if !ok {
+		candidates = unixClipboardTools
+	}
+
+	for _, candidate := range candidates {
+		if _, err := lookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, errors.ErrNoClipboard
+}
+
+// CopyToClipboard puts s on the system clipboard using the platform's
+// copy tool (pbcopy, clip, or xclip and friends).
+// It returns errors.ErrNoClipboard when no tool is available.
+func CopyToClipboard(s string) error {
+	command, err := clipboardCommand(runtime.GOOS)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(s)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	// Tools like xclip explain the failure (e.g. no display) on stderr
+	if detail := strings.TrimSpace(string(out)); detail != "" {
+		return errors.WrapWithContext(err, "%s failed: %s", command[0], detail)
+	}
+	return errors.WrapWithContext(err, "%s failed", command[0])
+}
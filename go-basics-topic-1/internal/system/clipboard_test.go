@@ -0,0 +1,73 @@
+package system
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// stubLookPath makes only the named tools appear installed.
+func stubLookPath(t *testing.T, installed ...string) {
+	t.Helper()
+	previous := lookPath
+	lookPath = func(file string) (string, error) {
+		for _, name := range installed {
+			if file == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	t.Cleanup(func() { lookPath = previous })
+}
+
+// TestClipboardCommand tests picking the copy tool for each platform.
+func TestClipboardCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		installed []string
+		expected  string
+		hasError  bool
+	}{
+		{"macOS", "darwin", []string{"pbcopy"}, "pbcopy", false},
+		{"windows", "windows", []string{"clip"}, "clip", false},
+		{"linux prefers xclip", "linux", []string{"xclip", "wl-copy"}, "xclip -selection clipboard", false},
+		{"linux falls back to xsel", "linux", []string{"xsel"}, "xsel --clipboard --input", false},
+		{"wayland", "linux", []string{"wl-copy"}, "wl-copy", false},
+		{"freebsd uses unix tools", "freebsd", []string{"xclip"}, "xclip -selection clipboard", false},
+		{"macOS ignores unix tools", "darwin", []string{"xclip"}, "", true},
+		{"nothing installed", "linux", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubLookPath(t, tt.installed...)
+
+			command, err := clipboardCommand(tt.goos)
+			if tt.hasError {
+				if !stderrors.Is(err, errors.ErrNoClipboard) {
+					t.Errorf("%s: expected ErrNoClipboard, got %v", tt.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if got := strings.Join(command, " "); got != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestCopyToClipboardNoTool tests the error when no copy tool is installed.
+func TestCopyToClipboardNoTool(t *testing.T) {
+	stubLookPath(t)
+
+	if err := CopyToClipboard("42"); !stderrors.Is(err, errors.ErrNoClipboard) {
+		t.Errorf("Expected ErrNoClipboard, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"cli-calculator/internal/errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Editor returns the user's preferred editor command, checking $VISUAL before
+// $EDITOR as most Unix tools do. It returns "" when neither is set.
+func Editor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return ""
+}
+
+// OpenEditor opens path in the user's editor and waits for it to exit.
+// The editor value may include arguments (e.g. "code --wait").
+// It returns errors.ErrNoEditor when no editor is configured.
+// This demonstrates running an external program with os/exec.
+func OpenEditor(path string) error {
+	editor := Editor()
+	if editor == "" {
+		return errors.ErrNoEditor
+	}
+
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+
+	// Hand the terminal to the editor
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.WrapWithContext(err, "editor %q failed", editor)
+	}
+	return nil
+}
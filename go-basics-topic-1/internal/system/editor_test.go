@@ -0,0 +1,49 @@
+package system
+
+import (
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenEditor tests launching a fake editor that creates a marker file.
+func TestOpenEditor(t *testing.T) {
+	if _, err := exec.LookPath("touch"); err != nil {
+		t.Skip("touch command not available")
+	}
+
+	marker := filepath.Join(t.TempDir(), "marker")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "touch")
+
+	if err := OpenEditor(marker); err != nil {
+		t.Fatalf("OpenEditor failed: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("Expected editor to create %s: %v", marker, err)
+	}
+}
+
+// TestOpenEditorNoEditor tests the error when no editor is configured.
+func TestOpenEditorNoEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+
+	err := OpenEditor(filepath.Join(t.TempDir(), "config.json"))
+	if !stderrors.Is(err, errors.ErrNoEditor) {
+		t.Errorf("Expected ErrNoEditor, got %v", err)
+	}
+}
+
+// TestEditorPrefersVisual tests that $VISUAL wins over $EDITOR.
+func TestEditorPrefersVisual(t *testing.T) {
+	t.Setenv("VISUAL", "code --wait")
+	t.Setenv("EDITOR", "vi")
+
+	if got := Editor(); got != "code --wait" {
+		t.Errorf("Expected %q, got %q", "code --wait", got)
+	}
+}
@@ -0,0 +1,51 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// rename is the final step of an atomic write.
+// It is a variable so tests can simulate a failure.
+var rename = os.Rename
+
+// WriteFileAtomic writes data to path without ever leaving a partially
+// written file behind. The data goes to a temporary file in the same
+// directory, which is then renamed over the target. A rename within one
+// directory is atomic, so readers see either the old or the new contents.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Clean up the temporary file if anything below fails
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	// Flush to disk before the rename makes the file visible
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	return rename(tmpPath, path)
+}
@@ -0,0 +1,80 @@
+// Package system provides system-level utilities with tests.
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteFileAtomic tests that a successful write replaces the file contents.
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write original file: %v", err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != "new" {
+		t.Errorf("Expected 'new', got '%s'", data)
+	}
+
+	// No temporary files should remain next to the target
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 file in directory, got %d", len(entries))
+	}
+}
+
+// TestWriteFileAtomicFailure tests that a failed write leaves the original untouched.
+func TestWriteFileAtomicFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	if err := os.WriteFile(path, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to write original file: %v", err)
+	}
+
+	// Simulate a crash between writing the temp file and replacing the target
+	rename = func(oldpath, newpath string) error {
+		return errors.New("simulated rename failure")
+	}
+	defer func() { rename = os.Rename }()
+
+	if err := WriteFileAtomic(path, []byte("corrupted"), 0644); err == nil {
+		t.Fatal("Expected error from failed write, got nil")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != "original" {
+		t.Errorf("Original file was modified: got '%s'", data)
+	}
+
+	// The temporary file must be cleaned up
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary file to be removed, found %d files", len(entries))
+	}
+}
+
+// TestWriteFileAtomicMissingDirectory tests writing into a directory that doesn't exist.
+func TestWriteFileAtomicMissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "data.json")
+
+	if err := WriteFileAtomic(path, []byte("data"), 0644); err == nil {
+		t.Error("Expected error for missing directory, got nil")
+	}
+}
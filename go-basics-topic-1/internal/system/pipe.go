@@ -0,0 +1,23 @@
+package system
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// IgnoreBrokenPipe stops a closed stdout (e.g. `calculator | head`) from
+// killing the process with SIGPIPE. Writes to it fail with EPIPE instead,
+// which IsBrokenPipe recognizes so the program can stop on its own terms.
+// Only call it when every stdout writer checks for that error.
+func IgnoreBrokenPipe() {
+	signal.Ignore(syscall.SIGPIPE)
+}
+
+// IsBrokenPipe reports whether err means the reading end of the output has
+// gone away: EPIPE from the OS, or a closed pipe or file.
+func IsBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed)
+}
@@ -0,0 +1,35 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestIsBrokenPipe tests which write errors mean the reader went away.
+func TestIsBrokenPipe(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"EPIPE", syscall.EPIPE, true},
+		{"wrapped EPIPE", &os.PathError{Op: "write", Path: "/dev/stdout", Err: syscall.EPIPE}, true},
+		{"closed pipe", io.ErrClosedPipe, true},
+		{"closed file", fmt.Errorf("write: %w", os.ErrClosed), true},
+		{"disk full", syscall.ENOSPC, false},
+		{"other error", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBrokenPipe(tt.err); got != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
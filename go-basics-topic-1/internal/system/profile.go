@@ -0,0 +1,27 @@
+package system
+
+import (
+	"os"
+	"runtime/pprof"
+)
+
+// StartCPUProfile starts writing a CPU profile to path, creating or
+// truncating the file. The returned stop function ends profiling and closes
+// the file; it must run before the program exits or the profile is empty.
+// Inspect the result with `go tool pprof <binary> <path>`.
+func StartCPUProfile(path string) (stop func() error, err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,49 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestStartCPUProfile tests that profiling some work writes a non-empty profile.
+func TestStartCPUProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpu.prof")
+
+	stop, err := StartCPUProfile(path)
+	if err != nil {
+		t.Fatalf("StartCPUProfile returned error: %v", err)
+	}
+
+	// Keep the CPU busy long enough for the profiler to take samples
+	sum := 0.0
+	for deadline := time.Now().Add(100 * time.Millisecond); time.Now().Before(deadline); {
+		for i := 0; i < 1000; i++ {
+			sum += float64(i) * 1.0001
+		}
+	}
+	_ = sum
+
+	if err := stop(); err != nil {
+		t.Fatalf("stop returned error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected profile at %s: %v", path, err)
+	}
+	if info.Size() == 0 {
+		t.Error("Expected a non-empty profile")
+	}
+}
+
+// TestStartCPUProfileBadPath tests that an unwritable path is reported.
+func TestStartCPUProfileBadPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "cpu.prof")
+
+	if stop, err := StartCPUProfile(path); err == nil {
+		stop()
+		t.Error("Expected error for a path in a missing directory, got nil")
+	}
+}
@@ -0,0 +1,47 @@
+package system
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Persister is anything that can write its state to disk, such as *history.History.
+type Persister interface {
+	Save() error
+}
+
+// FlushOnHangup saves p every time the process receives SIGHUP, without
+// exiting, and passes the outcome of each save to onFlush (nil on success).
+// It returns a function that stops listening for the signal.
+// This demonstrates os/signal with a goroutine and a done channel.
+func FlushOnHangup(p Persister, onFlush func(error)) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				onFlush(p.Save())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// InterruptContext returns a context that is cancelled when the process
+// receives SIGINT (Ctrl+C) or SIGTERM, so long-running work can stop at a
+// safe point instead of being killed. Call stop to restore the default
+// signal behavior once the work is done.
+func InterruptContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
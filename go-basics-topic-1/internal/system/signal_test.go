@@ -0,0 +1,123 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakePersister counts Save calls and returns a configurable error.
+type fakePersister struct {
+	saves int
+	err   error
+}
+
+// Save records the call.
+func (p *fakePersister) Save() error {
+	p.saves++
+	return p.err
+}
+
+// TestFlushOnHangup tests that a SIGHUP triggers a save and reports the outcome.
+func TestFlushOnHangup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP cannot be sent on Windows")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"successful save", nil},
+		{"failed save", errors.New("disk full")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fakePersister{err: tt.err}
+			flushed := make(chan error, 1)
+
+			stop := FlushOnHangup(p, func(err error) { flushed <- err })
+			defer stop()
+
+			if err := sendHangup(); err != nil {
+				t.Fatalf("%s: failed to send SIGHUP: %v", tt.name, err)
+			}
+
+			select {
+			case err := <-flushed:
+				if err != tt.err {
+					t.Errorf("%s: expected flush error %v, got %v", tt.name, tt.err, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: timed out waiting for flush", tt.name)
+			}
+			if p.saves != 1 {
+				t.Errorf("%s: expected 1 save, got %d", tt.name, p.saves)
+			}
+		})
+	}
+}
+
+// TestFlushOnHangupStop tests that the listener can be stopped cleanly.
+func TestFlushOnHangupStop(t *testing.T) {
+	p := &fakePersister{}
+	stop := FlushOnHangup(p, func(error) {})
+	stop()
+
+	if p.saves != 0 {
+		t.Errorf("Expected no saves without a signal, got %d", p.saves)
+	}
+}
+
+// TestInterruptContext tests that SIGINT cancels the context instead of
+// ending the process.
+func TestInterruptContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGINT cannot be sent to the process on Windows")
+	}
+
+	ctx, stop := InterruptContext(context.Background())
+	defer stop()
+
+	if err := sendSignal(os.Interrupt); err != nil {
+		t.Fatalf("Failed to send SIGINT: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the context to be cancelled")
+	}
+}
+
+// TestInterruptContextStop tests that stop cancels the context without a signal.
+func TestInterruptContextStop(t *testing.T) {
+	ctx, stop := InterruptContext(context.Background())
+	if ctx.Err() != nil {
+		t.Fatalf("Expected a live context, got %v", ctx.Err())
+	}
+
+	stop()
+	if ctx.Err() == nil {
+		t.Error("Expected stop to cancel the context")
+	}
+}
+
+// sendHangup delivers SIGHUP to the test process itself.
+func sendHangup() error {
+	return sendSignal(syscall.SIGHUP)
+}
+
+// sendSignal delivers sig to the test process itself.
+func sendSignal(sig os.Signal) error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return process.Signal(sig)
+}
@@ -1,8 +1,8 @@
-// Package system provides system-level utilities (placeholder for future expansion).
+// Package system provides system-level utilities such as safe file writes,
+// terminal detection, launching an editor, and signal handling.
 package system
 
-// This package is reserved for future system-level functionality such as:
-// - Signal handling
+// This package also reserves room for future system-level functionality such as:
 // - Process management
 // - System resource monitoring
 // - OS-specific utilities
@@ -0,0 +1,24 @@
+package system
+
+import (
+	"io"
+	"os"
+)
+
+// IsTerminal reports whether w is an interactive terminal. Only an *os.File
+// backed by a character device counts; pipes, regular files, and in-memory
+// writers do not.
+// This demonstrates a type assertion on an interface value.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
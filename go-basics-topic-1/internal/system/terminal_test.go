@@ -0,0 +1,34 @@
+package system
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestIsTerminal tests that non-terminal writers are detected.
+func TestIsTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name   string
+		writer io.Writer
+	}{
+		{"buffer", &bytes.Buffer{}},
+		{"regular file", file},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if IsTerminal(tt.writer) {
+				t.Errorf("%s: expected not a terminal", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"cli-calculator/internal/errors"
+	"strconv"
+	"strings"
+)
+
+// DefaultInputHistorySize is how many recent inputs interactive mode remembers.
+const DefaultInputHistorySize = 50
+
+// InputHistory remembers recent input lines in a fixed-size ring buffer so
+// they can be recalled with shell-style bang syntax. Inputs are numbered from
+// 1 in the order they were added; numbering keeps counting after old inputs
+// are overwritten.
+// This demonstrates a ring buffer built on a slice and modular arithmetic.
+type InputHistory struct {
+	lines []string // Ring buffer storage
+	total int      // Number of inputs ever added
+}
+
+// NewInputHistory creates an input history holding up to size lines.
+func NewInputHistory(size int) *InputHistory {
+	if size < 1 {
+		size = 1
+	}
+	return &InputHistory{lines: make([]string, size)}
+}
+
+// Add records an input line. Empty lines are ignored.
+func (h *InputHistory) Add(line string) {
+	if line == "" {
+		return
+	}
+	h.lines[h.total%len(h.lines)] = line
+	h.total++
+}
+
+// Len returns the total number of inputs added so far.
+func (h *InputHistory) Len() int {
+	return h.total
+}
+
+// Recall returns input number n (1-based). It reports false when n was never
+// added or has already been overwritten.
+func (h *InputHistory) Recall(n int) (string, bool) {
+	oldest := h.total - len(h.lines) + 1
+	if oldest < 1 {
+		oldest = 1
+	}
+	if n < oldest || n > h.total {
+		return "", false
+	}
+	return h.lines[(n-1)%len(h.lines)], true
+}
+
+// Expand performs bang expansion on a whole input line:
+//
+//	!!   the previous input
+//	!n   input number n
+//	!-n  the input n entries back (!-1 is the same as !!)
+//
+// Lines that don't match one of these forms are returned unchanged, so a
+// lone "!" (the factorial symbol) is left alone. A reference to an input
+// that isn't available returns a ValidationError.
+func (h *InputHistory) Expand(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") || len(line) < 2 {
+		return line, nil
+	}
+
+	var n int
+	switch ref := line[1:]; {
+	case ref == "!":
+		n = h.total
+	case strings.HasPrefix(ref, "-"):
+		back, err := strconv.Atoi(ref[1:])
+		if err != nil || back < 1 {
+			return line, nil
+		}
+		n = h.total - back + 1
+	default:
+		num, err := strconv.Atoi(ref)
+		if err != nil {
+			return line, nil
+		}
+		n = num
+	}
+
+	recalled, ok := h.Recall(n)
+	if !ok {
+		return "", errors.NewValidationError("history", line, "no such input in history")
+	}
+	return recalled, nil
+}
@@ -0,0 +1,100 @@
+// Package util provides input history recall with tests.
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestInputHistoryExpand tests bang expansion against a populated history.
+func TestInputHistoryExpand(t *testing.T) {
+	h := NewInputHistory(10)
+	h.Add("12.5")
+	h.Add("3")
+	h.Add("42")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"previous input", "!!", "42", false},
+		{"first input", "!1", "12.5", false},
+		{"second input", "!2", "3", false},
+		{"relative back one", "!-1", "42", false},
+		{"relative back three", "!-3", "12.5", false},
+		{"plain input unchanged", "7", "7", false},
+		{"lone bang unchanged", "!", "!", false},
+		{"non-numeric bang unchanged", "!abc", "!abc", false},
+		{"number out of range", "!4", "", true},
+		{"number zero", "!0", "", true},
+		{"relative out of range", "!-4", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := h.Expand(tt.input)
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				if result != tt.expected {
+					t.Errorf("%s: expected '%s', got '%s'", tt.name, tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestInputHistoryRingBuffer tests that old inputs are overwritten but numbering continues.
+func TestInputHistoryRingBuffer(t *testing.T) {
+	h := NewInputHistory(2)
+	h.Add("a")
+	h.Add("b")
+	h.Add("c")
+
+	if _, ok := h.Recall(1); ok {
+		t.Error("Expected input 1 to be overwritten")
+	}
+	if line, ok := h.Recall(3); !ok || line != "c" {
+		t.Errorf("Expected input 3 to be 'c', got '%s' (ok=%v)", line, ok)
+	}
+	if h.Len() != 3 {
+		t.Errorf("Expected 3 inputs added, got %d", h.Len())
+	}
+
+	// Empty lines are not recorded
+	h.Add("")
+	if h.Len() != 3 {
+		t.Errorf("Expected empty input to be ignored, got %d inputs", h.Len())
+	}
+}
+
+// TestIOGetUserInputRecall tests that bang references are expanded while reading input.
+func TestIOGetUserInputRecall(t *testing.T) {
+	var out bytes.Buffer
+	u := NewIO(strings.NewReader("12.5\n!!\n!9\n!1\n"), &out)
+
+	expected := []string{"12.5", "12.5", "12.5"}
+	for i, want := range expected {
+		got, err := u.GetUserInput("> ")
+		if err != nil {
+			t.Fatalf("Read %d: unexpected error: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("Read %d: expected '%s', got '%s'", i, want, got)
+		}
+	}
+
+	// The unknown reference (!9) was reported and re-prompted
+	if !strings.Contains(out.String(), "no such input") {
+		t.Errorf("Expected a warning for the unknown reference, got output: %q", out.String())
+	}
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"bufio"
+	"cli-calculator/internal/errors"
+	"cli-calculator/internal/system"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// IO bundles the reader and writer used for user interaction.
+// Keeping a single buffered reader matters: creating a new bufio.Reader per
+// prompt would drop any input already buffered from a pipe.
+type IO struct {
+	In      *bufio.Reader // Source of user input
+	Out     io.Writer     // Destination for prompts
+	History *InputHistory // Recent inputs for !! and !n recall; nil disables recall
+	Echo    bool          // Write each line read back to Out, for transcripts of piped input
+}
+
+// NewIO creates an IO reading from in and writing to out, with input recall enabled.
+func NewIO(in io.Reader, out io.Writer) *IO {
+	return &IO{
+		In:      bufio.NewReader(in),
+		Out:     &pipeWriter{w: out},
+		History: NewInputHistory(DefaultInputHistorySize),
+	}
+}
+
+// pipeWriter passes writes through to w and remembers once one fails because
+// the reader has gone away (see system.IsBrokenPipe).
+type pipeWriter struct {
+	w      io.Writer
+	closed bool
+}
+
+func (p *pipeWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if err != nil && system.IsBrokenPipe(err) {
+		p.closed = true
+	}
+	return n, err
+}
+
+// OutputClosed reports whether a write to Out has failed because its reader
+// went away, as when the calculator is piped into `head`.
+func (u *IO) OutputClosed() bool {
+	pipe, ok := u.Out.(*pipeWriter)
+	return ok && pipe.closed
+}
+
+// defaultIO is used by the package-level input functions.
+var defaultIO = NewIO(os.Stdin, os.Stdout)
+
+// DefaultIO returns the IO used by the package-level input functions.
+func DefaultIO() *IO {
+	return defaultIO
+}
+
+// SetDefaultIO replaces the IO used by the package-level input functions and
+// returns the previous one so callers (typically tests) can restore it.
+func SetDefaultIO(u *IO) *IO {
+	previous := defaultIO
+	defaultIO = u
+	return previous
+}
+
+// GetUserInput prompts the user and reads a line of input.
+// Bang references (!!, !n) are expanded from the input history and the
+// expansion is echoed so the user sees what was recalled. With Echo set the
+// line itself is echoed too, since piped input never shows up on the
+// terminal. Once nobody reads the output any more, it returns
+// errors.ErrOutputClosed instead of waiting.
+func (u *IO) GetUserInput(prompt string) (string, error) {
+	for {
+		fmt.Fprint(u.Out, prompt)
+		if u.OutputClosed() {
+			return "", errors.ErrOutputClosed
+		}
+
+		input, err := u.In.ReadString('\n')
+		if err != nil && (err != io.EOF || input == "") {
+			return "", errors.Wrap(err, "failed to read input")
+		}
+
+		// Trim whitespace and handle Windows line endings
+		input = strings.TrimSpace(input)
+		input = strings.TrimSuffix(input, "\r")
+		if u.Echo {
+			fmt.Fprintln(u.Out, input)
+		}
+
+		if u.History == nil {
+			return input, nil
+		}
+
+		expanded, err := u.History.Expand(input)
+		if err != nil {
+			fmt.Fprintf(u.Out, "⚠ Warning: %v\n", err)
+			continue
+		}
+		if expanded != input {
+			fmt.Fprintln(u.Out, expanded)
+		}
+
+		u.History.Add(expanded)
+		return expanded, nil
+	}
+}
+
+// PressEnterToContinue waits for the user to press Enter.
+func (u *IO) PressEnterToContinue() {
+	fmt.Fprint(u.Out, "Press Enter to continue...")
+	if u.OutputClosed() {
+		return
+	}
+	u.In.ReadString('\n')
+	if u.Echo {
+		fmt.Fprintln(u.Out)
+	}
+}
+
+// ClearScreen clears the terminal with an ANSI escape sequence, which works on
+// Unix-like systems and Windows 10+. Nothing is written unless Out is a terminal.
+func (u *IO) ClearScreen() {
+	out := u.Out
+	if pipe, ok := out.(*pipeWriter); ok {
+		out = pipe.w // The terminal check needs the *os.File underneath
+	}
+	if !system.IsTerminal(out) {
+		return
+	}
+	fmt.Fprint(u.Out, "\033[H\033[2J")
+}
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"bytes"
+	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestClearScreenNotTerminal tests that nothing is written when output isn't a TTY.
+func TestClearScreenNotTerminal(t *testing.T) {
+	var out bytes.Buffer
+	u := NewIO(strings.NewReader(""), &out)
+
+	u.ClearScreen()
+
+	if out.Len() != 0 {
+		t.Errorf("Expected no output for a non-terminal writer, got %q", out.String())
+	}
+}
+
+// closedWriter fails every write the way a pipe with no reader does.
+type closedWriter struct{}
+
+func (closedWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+// TestGetUserInputOutputClosed tests that prompting stops once nobody reads
+// the output, instead of carrying on with the input.
+func TestGetUserInputOutputClosed(t *testing.T) {
+	u := NewIO(strings.NewReader("1\n2\n"), closedWriter{})
+	if u.OutputClosed() {
+		t.Fatal("Expected output open before any write")
+	}
+
+	input, err := u.GetUserInput("Enter your choice: ")
+	if !stderrors.Is(err, errors.ErrOutputClosed) {
+		t.Errorf("Expected ErrOutputClosed, got %q, %v", input, err)
+	}
+	if !u.OutputClosed() {
+		t.Error("Expected output closed after a failed write")
+	}
+
+	// A working writer never reports closed
+	open := NewIO(strings.NewReader("1\n"), &bytes.Buffer{})
+	if input, err := open.GetUserInput("> "); err != nil || input != "1" || open.OutputClosed() {
+		t.Errorf("Expected input \"1\" from an open writer, got %q, %v", input, err)
+	}
+}
+
+// TestConfirmOverwrite tests asking before replacing an existing file.
+func TestConfirmOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		input    string
+		expected bool
+		prompted bool
+		hasError bool
+	}{
+		{"new file needs no prompt", filepath.Join(dir, "new.txt"), "", true, false, false},
+		{"existing file confirmed", existing, "y\n", true, true, false},
+		{"existing file declined", existing, "n\n", false, true, false},
+		{"directory refused", dir, "y\n", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := SetDefaultIO(NewIO(strings.NewReader(tt.input), &out))
+			defer SetDefaultIO(previous)
+
+			ok, err := ConfirmOverwrite(tt.path)
+			if tt.hasError != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", tt.name, tt.hasError, err)
+			}
+			if ok != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ok)
+			}
+			if prompted := strings.Contains(out.String(), "Overwrite"); prompted != tt.prompted {
+				t.Errorf("%s: expected prompt %v, got output %q", tt.name, tt.prompted, out.String())
+			}
+		})
+	}
+}
+
+// TestConfirmWithDefault tests that Enter picks the default and the prompt shows it.
+func TestConfirmWithDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		def      bool
+		input    string
+		expected bool
+		choices  string
+	}{
+		{"empty input defaults to yes", true, "\n", true, "(Y/n)"},
+		{"empty input defaults to no", false, "\n", false, "(y/N)"},
+		{"explicit no overrides yes default", true, "n\n", false, "(Y/n)"},
+		{"explicit yes overrides no default", false, "yes\n", true, "(y/N)"},
+		{"unrecognized input is no", true, "maybe\n", false, "(Y/n)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := SetDefaultIO(NewIO(strings.NewReader(tt.input), &out))
+			defer SetDefaultIO(previous)
+
+			ok, err := ConfirmWithDefault("Exit?", tt.def)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if ok != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ok)
+			}
+			if !strings.Contains(out.String(), "Exit? "+tt.choices) {
+				t.Errorf("%s: expected prompt with %s, got %q", tt.name, tt.choices, out.String())
+			}
+		})
+	}
+}
+
+// TestGetUserInputEcho tests that input lines are written after the prompt
+// only when Echo is set.
+func TestGetUserInputEcho(t *testing.T) {
+	tests := []struct {
+		name     string
+		echo     bool
+		input    string
+		expected string
+	}{
+		{"echo on", true, "5\n", "> 5\n"},
+		{"echo off", false, "5\n", "> "},
+		{"windows line ending", true, "7\r\n", "> 7\n"},
+		{"empty line", true, "\n", "> \n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			u := NewIO(strings.NewReader(tt.input), &out)
+			u.Echo = tt.echo
+
+			if _, err := u.GetUserInput("> "); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, out.String())
+			}
+		})
+	}
+}
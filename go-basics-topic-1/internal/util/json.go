@@ -0,0 +1,56 @@
+package util
+
+import (
+	"cli-calculator/internal/errors"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+)
+
+// errorOutput is the JSON shape of an error in machine-readable output.
+// Optional fields are filled from the custom error types when available.
+type errorOutput struct {
+	Error     string `json:"error"`
+	Operation string `json:"operation,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Path      string `json:"path,omitempty"`
+}
+
+// PrintJSON writes v as a single line of JSON.
+func PrintJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.WrapWithContext(err, "failed to marshal JSON output")
+	}
+	_, err = fmt.Fprintln(defaultIO.Out, string(data))
+	return err
+}
+
+// PrintErrorJSON writes err as JSON, e.g. {"error":"division by zero","operation":"Division"}.
+// errors.As extracts structured fields from the custom error types, so the
+// message is the short reason rather than the full wrapped error text.
+func PrintErrorJSON(err error) error {
+	var (
+		calcErr       *errors.CalculationError
+		validationErr *errors.ValidationError
+		fileErr       *errors.FileError
+	)
+
+	output := errorOutput{Error: err.Error()}
+	switch {
+	case stderrors.As(err, &calcErr):
+		output.Error = calcErr.Reason
+		output.Operation = calcErr.Operation
+	case stderrors.As(err, &validationErr):
+		output.Error = validationErr.Message
+		output.Field = validationErr.Field
+		output.Value = validationErr.Value
+	case stderrors.As(err, &fileErr):
+		output.Error = fileErr.Err.Error()
+		output.Operation = fileErr.Operation
+		output.Path = fileErr.Path
+	}
+
+	return PrintJSON(output)
+}
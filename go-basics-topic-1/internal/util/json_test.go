@@ -0,0 +1,56 @@
+// Package util provides JSON output helpers with tests.
+package util
+
+import (
+	"bytes"
+	"cli-calculator/internal/errors"
+	"strings"
+	"testing"
+)
+
+// TestPrintErrorJSON tests the JSON rendering of the custom error types.
+func TestPrintErrorJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			"calculation error",
+			errors.NewCalculationError("Division", []float64{1, 0}, "division by zero", errors.ErrDivisionByZero),
+			`{"error":"division by zero","operation":"Division"}`,
+		},
+		{
+			"validation error",
+			errors.NewValidationError("number", "abc", "not a valid number"),
+			`{"error":"not a valid number","field":"number","value":"abc"}`,
+		},
+		{
+			"wrapped calculation error",
+			errors.Wrap(errors.NewCalculationError("Modulo", nil, "division by zero in modulo operation", nil), "batch line 3"),
+			`{"error":"division by zero in modulo operation","operation":"Modulo"}`,
+		},
+		{
+			"plain error",
+			errors.ErrInvalidInput,
+			`{"error":"invalid input provided"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+			defer SetDefaultIO(previous)
+
+			if err := PrintErrorJSON(tt.err); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			result := strings.TrimSpace(out.String())
+			if result != tt.expected {
+				t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// justNow is how close a time must be to now to read as "just now".
+const justNow = 10 * time.Second
+
+// RelativeTime describes t relative to now in words, such as "just now",
+// "2 minutes ago", or "3 days ago". Times after now read as "in 5 minutes".
+// Each unit is rounded down, so 119 seconds is "1 minute ago". Taking now as
+// a parameter instead of calling time.Now keeps the output testable.
+func RelativeTime(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < justNow {
+		return "just now"
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		amount = plural(int(d/time.Second), "second")
+	case d < time.Hour:
+		amount = plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		amount = plural(int(d/time.Hour), "hour")
+	default:
+		amount = plural(int(d/(24*time.Hour)), "day")
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// plural formats a count with its unit, adding "s" unless the count is 1.
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRelativeTime tests describing times relative to now.
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		delta    time.Duration // How long before now
+		expected string
+	}{
+		{"same instant", 0, "just now"},
+		{"a few seconds", 9 * time.Second, "just now"},
+		{"seconds", 45 * time.Second, "45 seconds ago"},
+		{"one minute", time.Minute, "1 minute ago"},
+		{"rounds down", 119 * time.Second, "1 minute ago"},
+		{"minutes", 2 * time.Minute, "2 minutes ago"},
+		{"one hour", time.Hour, "1 hour ago"},
+		{"hours", 23*time.Hour + 59*time.Minute, "23 hours ago"},
+		{"one day", 24 * time.Hour, "1 day ago"},
+		{"days", 10 * 24 * time.Hour, "10 days ago"},
+		{"slightly in the future", -5 * time.Second, "just now"},
+		{"in the future", -5 * time.Minute, "in 5 minutes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RelativeTime(now.Add(-tt.delta), now)
+			if result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
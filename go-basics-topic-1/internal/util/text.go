@@ -0,0 +1,58 @@
+package util
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks text that Truncate shortened.
+const ellipsis = "…"
+
+// Truncate shortens s to at most max characters, replacing the end with an
+// ellipsis when it doesn't fit. Lengths count runes, not bytes, so multibyte
+// characters such as "√" are never split. A max of 0 or less disables
+// truncation.
+func Truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:max-1]) + ellipsis
+}
+
+// WrapText splits s into lines of at most width characters, breaking at
+// spaces. A word longer than width is split across lines. Blank text gives
+// a single empty line so it still takes up a row. A width of 0 or less
+// disables wrapping and returns s as one line.
+func WrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		// Hard-break words that can never fit on a line of their own
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestTruncate tests shortening text with an ellipsis.
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		max      int
+		expected string
+	}{
+		{"under the boundary", "1 + 2", 10, "1 + 2"},
+		{"at the boundary", "1 + 2 + 3", 9, "1 + 2 + 3"},
+		{"one over the boundary", "1 + 2 + 3", 8, "1 + 2 +…"},
+		{"well over the boundary", "10 + 20 + 30 + 40 + 50", 10, "10 + 20 +…"},
+		{"multibyte kept whole", "√16 → √25 → √36", 6, "√16 →…"},
+		{"multibyte at the boundary", "√√√", 3, "√√√"},
+		{"max of one", "12345", 1, "…"},
+		{"zero disables", "12345", 0, "12345"},
+		{"negative disables", "12345", -1, "12345"},
+		{"empty string", "", 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Truncate(tt.input, tt.max)
+			if result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+			if !utf8.ValidString(result) {
+				t.Errorf("%s: result %q is not valid UTF-8", tt.name, result)
+			}
+			if tt.max > 0 && utf8.RuneCountInString(result) > tt.max {
+				t.Errorf("%s: result %q is longer than %d characters", tt.name, result, tt.max)
+			}
+		})
+	}
+}
+
+// TestWrapText tests wrapping text at word boundaries.
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected []string
+	}{
+		{"fits on one line", "hello world", 20, []string{"hello world"}},
+		{"breaks at a space", "hello wide world", 10, []string{"hello wide", "world"}},
+		{"exact width", "abcde fghij", 5, []string{"abcde", "fghij"}},
+		{"collapses spaces", "a   b", 10, []string{"a b"}},
+		{"long word split", "abcdefghij xy", 4, []string{"abcd", "efgh", "ij", "xy"}},
+		{"multibyte counted once", "√√√ √√", 5, []string{"√√√", "√√"}},
+		{"word exactly the width", "abcd ef", 4, []string{"abcd", "ef"}},
+		{"long word after short", "ab cdefghij", 4, []string{"ab", "cdef", "ghij"}},
+		{"empty", "", 10, []string{""}},
+		{"zero width disables", "hello wide world", 0, []string{"hello wide world"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := WrapText(tt.input, tt.width)
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
@@ -3,118 +3,206 @@
 package util
 
 import (
-	"bufio"
 	"cli-calculator/internal/constants"
 	"cli-calculator/internal/errors"
 	"fmt"
+	"io"
 	"os"
-	"runtime"
 	"strings"
+	"unicode/utf8"
 )
 
-// DisplayWelcome displays the welcome banner.
+// outputWidth is the number of columns dividers, boxes, and wrapped help
+// text fill. Change it with SetOutputWidth.
+var outputWidth = constants.DefaultOutputWidth
+
+// defaultBanner is the welcome text shown below the title when no custom
+// banner is configured.
+const defaultBanner = "A simple yet powerful command-line calculator\nwith support for basic and advanced operations"
+
+// SetOutputWidth sets the width used for dividers, boxes, and wrapped help
+// text (from Config.OutputWidth) and returns the previous width so callers
+// (typically tests) can restore it.
+func SetOutputWidth(width int) int {
+	previous := outputWidth
+	outputWidth = width
+	return previous
+}
+
+// divider returns a horizontal rule as wide as the output.
+func divider() string {
+	return strings.Repeat("═", outputWidth)
+}
+
+// printWrapped writes text after prefix, wrapped to the output width, with
+// continuation lines indented to line up under the start of text.
+func printWrapped(out io.Writer, prefix, text string) {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	for i, line := range WrapText(text, outputWidth-len(indent)) {
+		if i > 0 {
+			prefix = indent
+		}
+		fmt.Fprintln(out, prefix+line)
+	}
+}
+
+// DisplayWelcome displays the welcome banner in a box as wide as the output.
+// A non-empty banner (from Config.BannerText) replaces the default title and
+// text and is word-wrapped to fit the box; newlines in it start new lines.
 // This demonstrates multi-line string output and formatting.
-func DisplayWelcome() {
-	fmt.Println("╔══════════════════════════════════════════════════════╗")
-	fmt.Printf("║              %s v%s              ║\n", constants.AppName, constants.AppVersion)
-	fmt.Println("╠══════════════════════════════════════════════════════╣")
-	fmt.Println("║  A simple yet powerful command-line calculator       ║")
-	fmt.Println("║  with support for basic and advanced operations      ║")
-	fmt.Println("╚══════════════════════════════════════════════════════╝")
-	fmt.Println()
+func DisplayWelcome(banner string) {
+	out := defaultIO.Out
+	inner := outputWidth - 2 // Columns between the box edges
+	textWidth := inner - 4   // Leaves a two-space margin on each side
+
+	fmt.Fprintf(out, "╔%s╗\n", strings.Repeat("═", inner))
+	if banner == "" {
+		// Center the title so the right edge lines up whatever the name and version
+		title := fmt.Sprintf("%s v%s", constants.AppName, constants.AppVersion)
+		left := (inner - utf8.RuneCountInString(title)) / 2
+		right := inner - utf8.RuneCountInString(title) - left
+		fmt.Fprintf(out, "║%s%s%s║\n", strings.Repeat(" ", left), title, strings.Repeat(" ", right))
+		fmt.Fprintf(out, "╠%s╣\n", strings.Repeat("═", inner))
+		banner = defaultBanner
+	}
+
+	for _, paragraph := range strings.Split(banner, "\n") {
+		for _, line := range WrapText(paragraph, textWidth) {
+			padding := textWidth - utf8.RuneCountInString(line)
+			fmt.Fprintf(out, "║  %s%s  ║\n", line, strings.Repeat(" ", padding))
+		}
+	}
+	fmt.Fprintf(out, "╚%s╝\n", strings.Repeat("═", inner))
+	fmt.Fprintln(out)
 }
 
-// DisplayMainMenu displays the main menu options.
+// MenuItem is one numbered entry of the main menu.
+type MenuItem struct {
+	Option constants.MenuOption
+	Label  string
+}
+
+// MainMenuItems returns the main menu entries in display order. The number
+// shown for each item is its MenuOption, so handlers and menu stay in sync.
+// Exit stays at the bottom even though Quick has the highest number.
+func MainMenuItems() []MenuItem {
+	return []MenuItem{
+		{constants.MenuBasicCalculator, "Basic Calculator (+, -, *, /)"},
+		{constants.MenuAdvancedCalculator, "Advanced Calculator (^, √, %, !)"},
+		{constants.MenuBatchCalculations, "Batch Calculations (what-if sweep)"},
+		{constants.MenuHistory, "Calculation History"},
+		{constants.MenuSettings, "Settings"},
+		{constants.MenuHelp, "Help & Instructions"},
+		{constants.MenuQuickOperations, "Quick (pinned operations)"},
+		{constants.MenuExit, "Exit"},
+	}
+}
+
+// DisplayMainMenu displays the main menu options from MainMenuItems.
 func DisplayMainMenu() {
-	fmt.Println("MAIN MENU:")
-	fmt.Println("════════════════════════════════════════════════════════")
-	fmt.Println("1. Basic Calculator (+, -, *, /)")
-	fmt.Println("2. Advanced Calculator (^, √, %, !)")
-	fmt.Println("3. Batch Calculations (multiple operations)")
-	fmt.Println("4. Calculation History")
-	fmt.Println("5. Settings")
-	fmt.Println("6. Help & Instructions")
-	fmt.Println("7. Exit")
-	fmt.Println("════════════════════════════════════════════════════════")
+	out := defaultIO.Out
+	fmt.Fprintln(out, "MAIN MENU:")
+	fmt.Fprintln(out, divider())
+	for _, item := range MainMenuItems() {
+		fmt.Fprintf(out, "%d. %s\n", item.Option, item.Label)
+	}
+	fmt.Fprintln(out, divider())
 }
 
 // DisplayBasicCalculatorMenu displays the basic calculator menu.
 func DisplayBasicCalculatorMenu() {
 	fmt.Println("BASIC CALCULATOR MENU:")
-	fmt.Println("════════════════════════════════════════════════════════")
+	fmt.Println(divider())
 	fmt.Println("Available Operations:")
 	fmt.Println("1. Addition (+)")
 	fmt.Println("2. Subtraction (-)")
 	fmt.Println("3. Multiplication (*)")
 	fmt.Println("4. Division (/)")
 	fmt.Println("0. Back to Main Menu")
-	fmt.Println("════════════════════════════════════════════════════════")
+	fmt.Println(divider())
 }
 
 // DisplayAdvancedCalculatorMenu displays the advanced calculator menu.
 func DisplayAdvancedCalculatorMenu() {
 	fmt.Println("ADVANCED CALCULATOR MENU:")
-	fmt.Println("════════════════════════════════════════════════════════")
+	fmt.Println(divider())
 	fmt.Println("Available Operations:")
 	fmt.Println("1. Power (x^y)")
 	fmt.Println("2. Square Root (√x)")
 	fmt.Println("3. Modulo (x % y)")
 	fmt.Println("4. Factorial (x!)")
+	fmt.Println("5. Percent Change (x → y)")
+	fmt.Println("6. Ratio (x:y)")
+	fmt.Println("7. Compound Interest (principal, rate, periods, years)")
+	fmt.Println("8. Round (x to n decimal places)")
+	fmt.Println("9. Average (mean of x, y, ...)")
+	fmt.Println("10. Quotient and Remainder (x ÷ y as whole number and remainder)")
 	fmt.Println("0. Back to Main Menu")
-	fmt.Println("════════════════════════════════════════════════════════")
+	fmt.Println(divider())
 }
 
-// DisplayHelp displays help information.
+// DisplayQuickMenu displays the pinned operations from the config, numbered
+// from 1 in the order they were pinned.
+func DisplayQuickMenu(operations []constants.Operation) {
+	out := defaultIO.Out
+	fmt.Fprintln(out, "QUICK OPERATIONS:")
+	fmt.Fprintln(out, divider())
+	for i, op := range operations {
+		fmt.Fprintf(out, "%d. %s (%s)\n", i+1, op.String(), op.Symbol())
+	}
+	fmt.Fprintln(out, "0. Back to Main Menu")
+	fmt.Fprintln(out, divider())
+}
+
+// DisplayHelp displays help information, wrapping descriptions to the
+// output width.
 func DisplayHelp() {
-	fmt.Println("HELP & INSTRUCTIONS:")
-	fmt.Println("════════════════════════════════════════════════════════")
-	fmt.Println("BASIC OPERATIONS:")
-	fmt.Println("  Addition       : Adds two or more numbers")
-	fmt.Println("  Subtraction    : Subtracts second number from first")
-	fmt.Println("  Multiplication : Multiplies two or more numbers")
-	fmt.Println("  Division       : Divides first number by second")
-	fmt.Println()
-	fmt.Println("ADVANCED OPERATIONS:")
-	fmt.Println("  Power          : Raises first number to power of second")
-	fmt.Println("  Square Root    : Calculates square root of a number")
-	fmt.Println("  Modulo         : Calculates remainder of division")
-	fmt.Println("  Factorial      : Calculates factorial (n!)")
-	fmt.Println()
-	fmt.Println("FEATURES:")
-	fmt.Println("  - History tracking of all calculations")
-	fmt.Println("  - Configurable precision for results")
-	fmt.Println("  - Persistent settings saved to disk")
-	fmt.Println("  - Error handling with detailed messages")
-	fmt.Println("════════════════════════════════════════════════════════")
+	out := defaultIO.Out
+	fmt.Fprintln(out, "HELP & INSTRUCTIONS:")
+	fmt.Fprintln(out, divider())
+	fmt.Fprintln(out, "BASIC OPERATIONS:")
+	printWrapped(out, "  Addition       : ", "Adds two or more numbers")
+	printWrapped(out, "  Subtraction    : ", "Subtracts second number from first")
+	printWrapped(out, "  Multiplication : ", "Multiplies two or more numbers")
+	printWrapped(out, "  Division       : ", "Divides first number by second")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "ADVANCED OPERATIONS:")
+	printWrapped(out, "  Power          : ", "Raises first number to power of second")
+	printWrapped(out, "  Square Root    : ", "Calculates square root of a number")
+	printWrapped(out, "  Modulo         : ", "Calculates remainder of division")
+	printWrapped(out, "  Factorial      : ", "Calculates factorial (n!)")
+	printWrapped(out, "  Percent Change : ", "Percentage change from first number to second")
+	printWrapped(out, "  Ratio          : ", "Ratio of two numbers in lowest terms (4:6 = 2:3)")
+	printWrapped(out, "  Compound Int.  : ", "Final amount of P at annual rate r, compounded n times a year for t years")
+	printWrapped(out, "  Round          : ", "Rounds to n decimal places, halves away from zero (2.5 → 3)")
+	printWrapped(out, "  Average        : ", "Mean of one or more numbers entered on one line (2 4 6 → 4)")
+	printWrapped(out, "  Quot. & Rem.   : ", "Whole-number quotient and remainder together (17 ÷ 5 = 3 remainder 2)")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "COMPLEXITY:")
+	for _, op := range constants.AllOperations {
+		printWrapped(out, fmt.Sprintf("  %-15s: ", op.String()), constants.OperationComplexity(op))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "FEATURES:")
+	printWrapped(out, "  - ", "History tracking of all calculations")
+	printWrapped(out, "  - ", "Configurable precision for results")
+	printWrapped(out, "  - ", "Persistent settings saved to disk")
+	printWrapped(out, "  - ", "Error handling with detailed messages")
+	printWrapped(out, "  - ", "Recall earlier inputs with !! (last) or !n (input n)")
+	printWrapped(out, "  - ", "Chain from the last result by entering an operator and number (e.g. * 2)")
+	fmt.Fprintln(out, divider())
 }
 
 // ClearScreen clears the terminal screen.
-// This demonstrates platform-specific behavior.
+// It does nothing when output is not a terminal, so piped output stays clean.
 func ClearScreen() {
-	// ANSI escape sequence works on Unix-like systems and Windows 10+
-	if runtime.GOOS == "windows" {
-		fmt.Print("\033[H\033[2J")
-	} else {
-		fmt.Print("\033[H\033[2J")
-	}
+	defaultIO.ClearScreen()
 }
 
 // GetUserInput prompts the user and reads a line of input.
 // This demonstrates I/O operations and error handling.
 func GetUserInput(prompt string) (string, error) {
-	fmt.Print(prompt)
-
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", errors.Wrap(err, "failed to read input")
-	}
-
-	// Trim whitespace and handle Windows line endings
-	input = strings.TrimSpace(input)
-	input = strings.TrimSuffix(input, "\r")
-
-	return input, nil
+	return defaultIO.GetUserInput(prompt)
 }
 
 // Confirm asks the user a yes/no question.
@@ -129,6 +217,45 @@ func Confirm(prompt string) (bool, error) {
 	return input == "y" || input == "yes", nil
 }
 
+// ConfirmWithDefault asks a yes/no question where pressing Enter picks def.
+// The default is capitalized in the prompt: "(Y/n)" or "(y/N)". Otherwise,
+// like Confirm, only "y" or "yes" count as yes.
+func ConfirmWithDefault(prompt string, def bool) (bool, error) {
+	choices := "(y/N)"
+	if def {
+		choices = "(Y/n)"
+	}
+
+	input, err := GetUserInput(prompt + " " + choices + ": ")
+	if err != nil {
+		return false, err
+	}
+
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return def, nil
+	}
+	return input == "y" || input == "yes", nil
+}
+
+// ConfirmOverwrite reports whether it is fine to write to path. A path that
+// doesn't exist yet needs no confirmation; an existing file asks the user first.
+// A directory is never overwritten.
+func ConfirmOverwrite(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.NewFileError(path, "stat", err)
+	}
+	if info.IsDir() {
+		return false, errors.NewFileError(path, "write", errors.ErrIsDirectory)
+	}
+
+	return Confirm(fmt.Sprintf("%s already exists. Overwrite it?", path))
+}
+
 // PrintSuccess prints a success message.
 func PrintSuccess(message string) {
 	fmt.Printf("✓ %s\n", message)
@@ -151,22 +278,30 @@ func PrintInfo(message string) {
 
 // PrintDivider prints a horizontal divider line.
 func PrintDivider() {
-	fmt.Println("════════════════════════════════════════════════════════")
+	fmt.Println(divider())
 }
 
 // PrintResult prints a formatted calculation result.
 func PrintResult(operation string, expression string, result string) {
+	PrintResultWithWords(operation, expression, result, "")
+}
+
+// PrintResultWithWords prints a calculation result followed by its spelled-out
+// form (see NumberToWords). An empty spoken string omits the extra line.
+func PrintResultWithWords(operation string, expression string, result string, spoken string) {
 	fmt.Println()
 	PrintDivider()
 	fmt.Printf("Operation : %s\n", operation)
 	fmt.Printf("Expression: %s\n", expression)
 	fmt.Printf("Result    : %s\n", result)
+	if spoken != "" {
+		fmt.Printf("In words  : %s\n", spoken)
+	}
 	PrintDivider()
 	fmt.Println()
 }
 
 // PressEnterToContinue waits for the user to press Enter.
 func PressEnterToContinue() {
-	fmt.Print("Press Enter to continue...")
-	bufio.NewReader(os.Stdin).ReadString('\n')
+	defaultIO.PressEnterToContinue()
 }
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"bytes"
+	"cli-calculator/internal/constants"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestDisplayWelcome tests the default and custom welcome banners.
+func TestDisplayWelcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		banner   string
+		contains []string
+		missing  string
+	}{
+		{"default when empty", "", []string{"A simple yet powerful command-line calculator"}, ""},
+		{"custom replaces default", "Acme Corp Calculator", []string{"║  Acme Corp Calculator"}, "A simple yet powerful"},
+		{
+			"long banner wraps",
+			"Welcome to the Acme Corp internal calculator, maintained by the finance tooling team",
+			[]string{"Welcome to the Acme Corp internal calculator,", "maintained by the finance tooling team"},
+			"A simple yet powerful",
+		},
+		{"newlines start lines", "Line one\nLine two", []string{"║  Line one ", "║  Line two "}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+			defer SetDefaultIO(previous)
+
+			DisplayWelcome(tt.banner)
+
+			for _, want := range tt.contains {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("%s: expected banner to contain %q, got:\n%s", tt.name, want, out.String())
+				}
+			}
+			if tt.missing != "" && strings.Contains(out.String(), tt.missing) {
+				t.Errorf("%s: expected banner without %q, got:\n%s", tt.name, tt.missing, out.String())
+			}
+
+			// Every row of the box has the same width
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				if n := utf8.RuneCountInString(line); n != 56 {
+					t.Errorf("%s: expected 56-character rows, got %d in %q", tt.name, n, line)
+				}
+			}
+		})
+	}
+}
+
+// TestOutputWidth tests that the welcome box, dividers, and help text all
+// fit the configured width.
+func TestOutputWidth(t *testing.T) {
+	for _, width := range []int{constants.MinOutputWidth, 40, constants.DefaultOutputWidth, 100} {
+		t.Run(strconv.Itoa(width), func(t *testing.T) {
+			var out bytes.Buffer
+			previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+			defer SetDefaultIO(previous)
+			defer SetOutputWidth(SetOutputWidth(width))
+
+			DisplayWelcome("")
+			DisplayWelcome("Welcome to the Acme Corp internal calculator")
+			DisplayHelp()
+			DisplayQuickMenu([]constants.Operation{constants.OpDivision})
+
+			for _, line := range strings.Split(out.String(), "\n") {
+				n := utf8.RuneCountInString(line)
+				if n > width {
+					t.Errorf("width %d: line of %d characters: %q", width, n, line)
+				}
+				if strings.HasPrefix(line, "═") && n != width {
+					t.Errorf("width %d: expected a divider of %d, got %d", width, width, n)
+				}
+				if strings.HasPrefix(line, "╔") || strings.HasPrefix(line, "║") {
+					if n != width {
+						t.Errorf("width %d: expected box rows of %d, got %d in %q", width, width, n, line)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestDisplayHelpWraps tests that long help descriptions continue on an
+// indented line instead of overflowing.
+func TestDisplayHelpWraps(t *testing.T) {
+	var out bytes.Buffer
+	previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+	defer SetDefaultIO(previous)
+	defer SetOutputWidth(SetOutputWidth(50))
+
+	DisplayHelp()
+
+	expected := "  Compound Int.  : Final amount of P at annual\n" +
+		"                   rate r, compounded n times a\n" +
+		"                   year for t years\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected wrapped compound interest help, got:\n%s", out.String())
+	}
+}
+
+// TestDisplayQuickMenu tests that pinned operations are listed in order.
+func TestDisplayQuickMenu(t *testing.T) {
+	var out bytes.Buffer
+	previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+	defer SetDefaultIO(previous)
+
+	DisplayQuickMenu([]constants.Operation{constants.OpDivision, constants.OpPower})
+
+	for _, want := range []string{"1. Division (/)\n2. Power (^)\n", "0. Back to Main Menu"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected menu to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+// TestMainMenuItems tests that every menu option has exactly one item and
+// that the menu shows each one under its option number.
+func TestMainMenuItems(t *testing.T) {
+	items := MainMenuItems()
+	seen := make(map[constants.MenuOption]bool)
+	for _, item := range items {
+		if seen[item.Option] {
+			t.Errorf("Menu option %d listed twice", item.Option)
+		}
+		seen[item.Option] = true
+		if item.Label == "" {
+			t.Errorf("Menu option %d has no label", item.Option)
+		}
+	}
+	for option := constants.MenuOption(constants.MinMenuOption); option <= constants.MaxMenuOption; option++ {
+		if !seen[option] {
+			t.Errorf("Menu option %d has no menu item", option)
+		}
+	}
+	if len(items) != constants.MaxMenuOption-constants.MinMenuOption+1 {
+		t.Errorf("Expected %d menu items, got %d", constants.MaxMenuOption-constants.MinMenuOption+1, len(items))
+	}
+
+	var out bytes.Buffer
+	previous := SetDefaultIO(NewIO(strings.NewReader(""), &out))
+	defer SetDefaultIO(previous)
+
+	DisplayMainMenu()
+	for _, item := range items {
+		if want := strconv.Itoa(int(item.Option)) + ". " + item.Label + "\n"; !strings.Contains(out.String(), want) {
+			t.Errorf("Expected menu to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
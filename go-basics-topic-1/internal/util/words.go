@@ -0,0 +1,80 @@
+package util
+
+import (
+	"strings"
+)
+
+// Number words in (US) English. Index 0 of tensWords is unused.
+var (
+	onesWords = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tensWords = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	scaleWords = []string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion",
+	}
+)
+
+// NumberToWords spells out an integer in English,
+// e.g. 1234 becomes "one thousand two hundred thirty-four".
+// This demonstrates breaking a number into groups of three digits.
+func NumberToWords(n int64) string {
+	if n == 0 {
+		return onesWords[0]
+	}
+
+	// Work on the magnitude as uint64 so math.MinInt64 doesn't overflow
+	magnitude := uint64(n)
+	if n < 0 {
+		magnitude = uint64(-(n + 1)) + 1
+	}
+
+	// Collect three-digit groups from the lowest scale upwards
+	parts := make([]string, 0)
+	for scale := 0; magnitude > 0; scale++ {
+		group := int(magnitude % 1000)
+		magnitude /= 1000
+		if group == 0 {
+			continue
+		}
+
+		words := groupToWords(group)
+		if scaleWords[scale] != "" {
+			words += " " + scaleWords[scale]
+		}
+		parts = append([]string{words}, parts...)
+	}
+
+	result := strings.Join(parts, " ")
+	if n < 0 {
+		result = "negative " + result
+	}
+	return result
+}
+
+// groupToWords spells out a number from 1 to 999.
+func groupToWords(n int) string {
+	parts := make([]string, 0, 2)
+
+	if n >= 100 {
+		parts = append(parts, onesWords[n/100]+" hundred")
+		n %= 100
+	}
+
+	switch {
+	case n == 0:
+		// Nothing left after the hundreds
+	case n < 20:
+		parts = append(parts, onesWords[n])
+	case n%10 == 0:
+		parts = append(parts, tensWords[n/10])
+	default:
+		parts = append(parts, tensWords[n/10]+"-"+onesWords[n%10])
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNumberToWords tests spelling out integers.
+func TestNumberToWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    int64
+		expected string
+	}{
+		{"zero", 0, "zero"},
+		{"single digit", 7, "seven"},
+		{"teen", 13, "thirteen"},
+		{"round tens", 40, "forty"},
+		{"hyphenated", 42, "forty-two"},
+		{"hundreds", 305, "three hundred five"},
+		{"example", 1234, "one thousand two hundred thirty-four"},
+		{"round thousands", 5000, "five thousand"},
+		{"round thousands with hundreds", 12000, "twelve thousand"},
+		{"skipped group", 1000001, "one million one"},
+		{"round million", 3000000, "three million"},
+		{"negative", -15, "negative fifteen"},
+		{"negative thousands", -2500, "negative two thousand five hundred"},
+		{"max int64", math.MaxInt64, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+		{"min int64", math.MinInt64, "negative nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NumberToWords(tt.input)
+			if result != tt.expected {
+				t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
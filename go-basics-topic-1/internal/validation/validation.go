@@ -5,10 +5,16 @@ package validation
 import (
 	"cli-calculator/internal/constants"
 	"cli-calculator/internal/errors"
+	stderrors "errors"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
+// SpecialValueMessage is the validation message for inf/nan number input.
+const SpecialValueMessage = "special values not allowed (inf, nan)"
+
 // ValidateMenuOption validates main menu input.
 // This demonstrates validation with custom error types.
 func ValidateMenuOption(input string) (constants.MenuOption, error) {
@@ -26,7 +32,7 @@ func ValidateMenuOption(input string) (constants.MenuOption, error) {
 		return 0, errors.NewValidationError(
 			"menu_option",
 			trimmed,
-			"must be between 1 and 7",
+			fmt.Sprintf("must be between %d and %d", constants.MinMenuOption, constants.MaxMenuOption),
 		)
 	}
 
@@ -67,6 +73,20 @@ func ValidateBasicOperation(input string) (constants.Operation, error) {
 // ValidateNumber validates and parses a number input.
 // This demonstrates float parsing with validation and error handling.
 func ValidateNumber(input string) (float64, error) {
+	return ValidateNumberWithSeparator(input, constants.DefaultDecimalSeparator)
+}
+
+// ValidateNumberWithSeparator validates and parses a number written with the
+// given decimal separator (e.g., "," for "3,14"). The separator is normalized
+// to "." before parsing. With a "," separator, input that also contains "."
+// is rejected as ambiguous, since "1.234,5" style grouping isn't supported.
+func ValidateNumberWithSeparator(input, separator string) (float64, error) {
+	return ValidateNumberInRange(input, separator, constants.MinNumberInputValue, constants.MaxNumberInputValue)
+}
+
+// ValidateNumberInRange is ValidateNumberWithSeparator with configurable bounds,
+// such as a floor of 0 to reject negative numbers.
+func ValidateNumberInRange(input, separator string, min, max float64) (float64, error) {
 	// Clean the input
 	trimmed := strings.TrimSpace(input)
 
@@ -75,24 +95,138 @@ func ValidateNumber(input string) (float64, error) {
 		return 0, errors.NewValidationError("number", trimmed, "cannot be empty")
 	}
 
+	// Normalize the decimal separator
+	normalized := trimmed
+	if separator != "" && separator != constants.DefaultDecimalSeparator {
+		if strings.Contains(trimmed, constants.DefaultDecimalSeparator) {
+			return 0, errors.NewValidationError(
+				"number",
+				trimmed,
+				fmt.Sprintf("ambiguous decimal separator (expected '%s')", separator),
+			)
+		}
+		normalized = strings.Replace(trimmed, separator, constants.DefaultDecimalSeparator, -1)
+	}
+
 	// Parse as float64
-	num, err := strconv.ParseFloat(trimmed, 64)
+	num, err := strconv.ParseFloat(normalized, 64)
 	if err != nil {
 		return 0, errors.NewValidationError("number", trimmed, "not a valid number")
 	}
 
+	// ParseFloat accepts "inf", "infinity", and "nan"; reject them here
+	// rather than letting them fail deep inside the calculator
+	if math.IsInf(num, 0) || math.IsNaN(num) {
+		return 0, errors.NewValidationError("number", trimmed, SpecialValueMessage)
+	}
+
+	// ParseFloat also accepts hex floats ("0x1p4") and digit separators
+	// ("0x_1p0"); only plain decimal notation is a valid calculator input
+	if !isDecimalLiteral(normalized) {
+		return 0, errors.NewValidationError("number", trimmed, "not a valid number")
+	}
+
 	// Validate range
-	if num > constants.MaxNumberInputValue || num < constants.MinNumberInputValue {
+	if num > max || num < min {
 		return 0, errors.NewValidationError(
 			"number",
 			trimmed,
-			"value out of allowed range",
+			fmt.Sprintf("value out of allowed range (%g to %g)", min, max),
+		)
+	}
+
+	return num, nil
+}
+
+// ValidateInteger validates and parses a whole-number input for operations
+// that only accept integers, such as factorial. Unlike ValidateNumber it
+// rejects fractions like "10.5" up front instead of leaving the integer check
+// to the calculator, and reports values beyond the int64 range. Any number
+// that is whole is accepted, however it is written ("1e3", "10.0").
+func ValidateInteger(input string) (int64, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return 0, errors.NewValidationError("integer", trimmed, "cannot be empty")
+	}
+
+	num, err := strconv.ParseInt(trimmed, 10, 64)
+	if err == nil {
+		return num, nil
+	}
+
+	// Tell overflow and fractions apart from input that isn't a number at all
+	if stderrors.Is(err, strconv.ErrRange) {
+		return 0, errors.NewValidationError(
+			"integer",
+			trimmed,
+			fmt.Sprintf("value out of allowed range (%d to %d)", int64(math.MinInt64), int64(math.MaxInt64)),
+		)
+	}
+	// Any float syntax; the int64 range is checked below
+	value, floatErr := ValidateNumberInRange(trimmed, constants.DefaultDecimalSeparator, -math.MaxFloat64, math.MaxFloat64)
+	if floatErr != nil {
+		return 0, errors.NewValidationError("integer", trimmed, "not a valid number")
+	}
+	if value != math.Trunc(value) {
+		return 0, errors.NewValidationError("integer", trimmed, "must be a whole number")
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, so the upper bound is exclusive
+	if value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, errors.NewValidationError(
+			"integer",
+			trimmed,
+			fmt.Sprintf("value out of allowed range (%d to %d)", int64(math.MinInt64), int64(math.MaxInt64)),
+		)
+	}
+	return int64(value), nil
+}
+
+// ValidateIntegerInRange is ValidateInteger with the same operand limits as
+// ValidateNumberInRange, so integer prompts honor min_operand and max_operand.
+func ValidateIntegerInRange(input string, min, max float64) (int64, error) {
+	num, err := ValidateInteger(input)
+	if err != nil {
+		return 0, err
+	}
+
+	if float64(num) > max || float64(num) < min {
+		return 0, errors.NewValidationError(
+			"integer",
+			strings.TrimSpace(input),
+			fmt.Sprintf("value out of allowed range (%g to %g)", min, max),
 		)
 	}
 
 	return num, nil
 }
 
+// isDecimalLiteral reports whether s uses only ASCII decimal digits, signs,
+// a decimal point, and an exponent marker.
+func isDecimalLiteral(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789+-.eE", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateNumbers validates every input instead of stopping at the first error.
+// Both returned slices have one element per input: values[i] holds the parsed
+// number (0 when invalid) and errs[i] is nil or the error for inputs[i], so a
+// caller can report all bad entries at once.
+// This demonstrates index-aligned result slices as an alternative to early return.
+func ValidateNumbers(inputs []string) ([]float64, []error) {
+	values := make([]float64, len(inputs))
+	errs := make([]error, len(inputs))
+
+	for i, input := range inputs {
+		values[i], errs[i] = ValidateNumber(input)
+	}
+
+	return values, errs
+}
+
 // ValidatePrecision validates precision input for number formatting.
 func ValidatePrecision(precision int) error {
 	if precision < 0 || precision > 15 {
@@ -4,6 +4,11 @@ package validation
 
 import (
 	"cli-calculator/internal/constants"
+	apperrors "cli-calculator/internal/errors"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -16,9 +21,11 @@ func TestValidateMenuOption(t *testing.T) {
 		hasError bool
 	}{
 		{"valid option 1", "1", constants.MenuBasicCalculator, false},
+		{"valid option 4", "4", constants.MenuHistory, false},
 		{"valid option 7", "7", constants.MenuExit, false},
+		{"valid option 8", "8", constants.MenuQuickOperations, false},
 		{"invalid option 0", "0", 0, true},
-		{"invalid option 8", "8", 0, true},
+		{"invalid option 9", "9", 0, true},
 		{"non-numeric", "abc", 0, true},
 		{"empty string", "", 0, true},
 		{"with spaces", " 3 ", constants.MenuBatchCalculations, false},
@@ -100,6 +107,13 @@ func TestValidateNumber(t *testing.T) {
 		{"empty string", "", 0, true},
 		{"just a dot", ".", 0, true},
 		{"multiple dots", "1.2.3", 0, true},
+		{"whitespace only", " \t\n", 0, true},
+		{"sign dot exponent", "+.e", 0, true},
+		{"hex float", "0x1p4", 0, true},
+		{"digit separator", "0x_1p0", 0, true},
+		{"arabic-indic digits", "٤٢", 0, true},
+		{"fullwidth digits", "４２", 0, true},
+		{"overflow", "1e400", 0, true},
 	}
 
 	for _, tt := range tests {
@@ -122,6 +136,213 @@ func TestValidateNumber(t *testing.T) {
 	}
 }
 
+// TestValidateInteger tests parsing whole numbers for integer-only operations.
+func TestValidateInteger(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+		message  string // Expected error message; empty means no error
+	}{
+		{"integer", "10", 10, ""},
+		{"negative", "-7", -7, ""},
+		{"with spaces", " 42 ", 42, ""},
+		{"int64 max", "9223372036854775807", math.MaxInt64, ""},
+		{"fraction", "10.5", 0, "must be a whole number"},
+		{"scientific notation", "1e3", 1000, ""},
+		{"whole decimal", "10.0", 10, ""},
+		{"fractional scientific notation", "1.5e0", 0, "must be a whole number"},
+		{"scientific overflow", "1e19", 0, "value out of allowed range"},
+		{"overflow", "9223372036854775808", 0, "value out of allowed range"},
+		{"negative overflow", "-9223372036854775809", 0, "value out of allowed range"},
+		{"non-numeric", "ten", 0, "not a valid number"},
+		{"empty", "", 0, "cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateInteger(tt.input)
+
+			if tt.message != "" {
+				var validationErr *apperrors.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("%s: expected ValidationError, got %v", tt.name, err)
+				}
+				if !strings.HasPrefix(validationErr.Message, tt.message) {
+					t.Errorf("%s: expected message %q, got %q", tt.name, tt.message, validationErr.Message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestValidateIntegerInRange tests the operand limits on integer input.
+func TestValidateIntegerInRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		min, max float64
+		expected int64
+		hasError bool
+	}{
+		{"within range", "12", 0, 100, 12, false},
+		{"at the limit", "100", 0, 100, 100, false},
+		{"above max", "101", 0, 100, 0, true},
+		{"below min", "-1", 0, 100, 0, true},
+		{"scientific above max", "1e3", 0, 100, 0, true},
+		{"fraction", "2.5", 0, 100, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateIntegerInRange(tt.input, tt.min, tt.max)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %d", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestValidateNumberSpecialValues tests that inf and nan are rejected up front.
+func TestValidateNumberSpecialValues(t *testing.T) {
+	inputs := []string{"inf", "-Inf", "+inf", "Infinity", "NaN", "nan"}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			_, err := ValidateNumber(input)
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", input)
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("%s: expected ValidationError, got %T", input, err)
+			}
+			if validationErr.Message != SpecialValueMessage {
+				t.Errorf("%s: expected message '%s', got '%s'", input, SpecialValueMessage, validationErr.Message)
+			}
+		})
+	}
+}
+
+// TestValidateNumberWithSeparator tests parsing with a locale decimal separator.
+func TestValidateNumberWithSeparator(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		separator string
+		expected  float64
+		hasError  bool
+	}{
+		{"comma decimal", "3,14", ",", 3.14, false},
+		{"comma negative", "-2,5", ",", -2.5, false},
+		{"comma integer", "42", ",", 42, false},
+		{"dot with comma separator is ambiguous", "3.14", ",", 0, true},
+		{"mixed separators are ambiguous", "1.234,5", ",", 0, true},
+		{"multiple commas", "1,2,3", ",", 0, true},
+		{"dot decimal", "3.14", ".", 3.14, false},
+		{"comma with dot separator", "3,14", ".", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateNumberWithSeparator(tt.input, tt.separator)
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got nil", tt.name)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("%s: unexpected error: %v", tt.name, err)
+				}
+				if result != tt.expected {
+					t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateNumberInRange tests validation against a configured floor and cap.
+func TestValidateNumberInRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		min, max float64
+		expected float64
+		hasError bool
+	}{
+		{"floor 0 accepts positive", "5", 0, 100, 5, false},
+		{"floor 0 accepts zero", "0", 0, 100, 0, false},
+		{"floor 0 rejects negative", "-5", 0, 100, 0, true},
+		{"above cap", "101", 0, 100, 0, true},
+		{"negative floor", "-5", -10, 10, -5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateNumberInRange(tt.input, ".", tt.min, tt.max)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("%s: expected error, got %f", tt.name, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			if result != tt.expected {
+				t.Errorf("%s: expected %f, got %f", tt.name, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestValidateNumbers tests that every input is validated and errors align by index.
+func TestValidateNumbers(t *testing.T) {
+	inputs := []string{"1.5", "abc", "-3", "", "inf", "42"}
+	expectedValues := []float64{1.5, 0, -3, 0, 0, 42}
+	expectedErrors := []bool{false, true, false, true, true, false}
+
+	values, errs := ValidateNumbers(inputs)
+
+	if len(values) != len(inputs) || len(errs) != len(inputs) {
+		t.Fatalf("expected %d values and errors, got %d and %d", len(inputs), len(values), len(errs))
+	}
+
+	for i, input := range inputs {
+		if (errs[i] != nil) != expectedErrors[i] {
+			t.Errorf("input[%d] %q: expected error %v, got %v", i, input, expectedErrors[i], errs[i])
+		}
+		if values[i] != expectedValues[i] {
+			t.Errorf("input[%d] %q: expected %f, got %f", i, input, expectedValues[i], values[i])
+		}
+	}
+
+	// Empty input produces empty, non-nil results
+	values, errs = ValidateNumbers(nil)
+	if len(values) != 0 || len(errs) != 0 {
+		t.Errorf("expected no results for nil input, got %v and %v", values, errs)
+	}
+}
+
 // TestValidatePrecision tests precision validation.
 func TestValidatePrecision(t *testing.T) {
 	tests := []struct {
@@ -197,3 +418,34 @@ func TestValidateYesNo(t *testing.T) {
 		})
 	}
 }
+
+// FuzzValidateNumber checks that ValidateNumber never panics and only ever
+// accepts finite, in-range values that survive a round trip.
+func FuzzValidateNumber(f *testing.F) {
+	seeds := []string{
+		"42", "-15", "3.14", "-2.5", "0", "1.5e2", " 10.5 ", "abc", "", ".", "1.2.3",
+		"inf", "-Inf", "NaN", "+.e", " \t\n", "0x1p4", "1_000", "1e400", "-0",
+		"٤٢", "４２", " 42 ", "​1", "1́",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		value, err := ValidateNumber(input)
+		if err != nil {
+			return
+		}
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			t.Fatalf("%q: accepted special value %v", input, value)
+		}
+		if value > constants.MaxNumberInputValue || value < constants.MinNumberInputValue {
+			t.Fatalf("%q: accepted out-of-range value %v", input, value)
+		}
+
+		again, err := ValidateNumber(strconv.FormatFloat(value, 'g', -1, 64))
+		if err != nil || again != value {
+			t.Fatalf("%q: round trip of %v gave %v, %v", input, value, again, err)
+		}
+	})
+}